@@ -0,0 +1,274 @@
+// Package cm builds elliptic curves of a prescribed number of points by complex multiplication (CM).
+package cm
+
+import (
+	"crypto/rand"
+	"fmt"
+	"goschoof/ec"
+	"goschoof/polynom"
+	"goschoof/utils"
+	"math/big"
+)
+
+// MaxDiscriminant the largest |D| accepted: the Hilbert class polynomial has degree the class number h(D) ~ sqrt(|D|),
+// and coefficients of about π·sqrt(|D|)·sum(1/a) bits
+const MaxDiscriminant = 1 << 16
+
+// orderDraws number of random points checked by orderIs
+const orderDraws = 20
+
+// twistAttempts number of random twists tried for j = 0 and j = 1728, which have 6 and 4 twists
+const twistAttempts = 64
+
+// Curve A curve built by complex multiplication: its number of points is N = p + 1 - T, with 4p = T² + |D|V².
+type Curve struct {
+	Curve *ec.EllipticCurve
+	D     int64    // the CM discriminant
+	J     *big.Int // the j-invariant, a root of H_D mod p
+	T     *big.Int // the trace of the Frobenius
+	N     *big.Int // the number of points
+}
+
+func (c *Curve) String() string {
+	return fmt.Sprintf("y² = x³ + %sx + %s mod %s, D = %d, j = %s, t = %s, N = %s",
+		c.Curve.GetA(), c.Curve.GetB(), c.Curve.GetP(), c.D, c.J, c.T, c.N)
+}
+
+// FindDiscriminant Returns the fundamental discriminant D of smallest |D| <= maxAbsD such as 4p = t² + |D|v²,
+// with the (t, v) found by Cornacchia: the curves of traces ±t (and the other traces of Traces) have CM by D.
+func FindDiscriminant(p *big.Int, maxAbsD int64) (D int64, t, v *big.Int, err error) {
+	for D := int64(-3); D >= -maxAbsD; D-- {
+		if !isFundamental(D) {
+			continue
+		}
+		if t, v, ok := Cornacchia(D, p); ok {
+			return D, t, v, nil
+		}
+	}
+	return 0, nil, nil, fmt.Errorf("No discriminant of absolute value up to %d for p %s.\n", maxAbsD, p)
+}
+
+// Traces Returns the traces of the curves mod p with CM by D, 4p = t² + |D|v²: ±t, plus ±2v for D = -4
+// and ±(t ± 3v)/2 for D = -3, whose curves have more twists.
+func Traces(D int64, t, v *big.Int) []*big.Int {
+	var traces []*big.Int
+	both := func(x *big.Int) {
+		traces = append(traces, new(big.Int).Set(x), new(big.Int).Neg(x))
+	}
+	both(t)
+	switch D {
+	case -4:
+		both(new(big.Int).Lsh(v, 1))
+	case -3:
+		v3 := new(big.Int).Mul(v, big.NewInt(3))
+		both(new(big.Int).Rsh(new(big.Int).Add(t, v3), 1))
+		both(new(big.Int).Rsh(new(big.Int).Sub(t, v3), 1))
+	}
+	return traces
+}
+
+// Construct Returns a curve mod p (p > 3 prime) of trace t, i.e. of N = p + 1 - t points, with CM by D,
+// 4p = t² + |D|v² (e.g. from FindDiscriminant): a root j of the Hilbert class polynomial H_D mod p is the j-invariant
+// of curves of the traces of Traces, the right one among its twists being told by its number of points, checked
+// with MultiplyPointByScalar on random points (see orderIs).
+// For j ≠ 0, 1728, y² = x³ + 3kx + 2k, k = j / (1728 - j), and its quadratic twist by a non-residue c,
+// y² = x³ + 3kc²x + 2kc³; for j = 0 (D = -3) the six twists y² = x³ + b, and for j = 1728 (D = -4)
+// the four twists y² = x³ + ax, are drawn at random.
+func Construct(p *big.Int, D int64, t *big.Int) (*Curve, error) {
+	if p.Cmp(big.NewInt(3)) <= 0 || !p.ProbablyPrime(20) {
+		return nil, fmt.Errorf("p %s must be a prime > 3.\n", p)
+	}
+	if !isDiscriminant(D) {
+		return nil, fmt.Errorf("%d is not a negative discriminant.\n", D)
+	}
+	if -D > MaxDiscriminant {
+		return nil, fmt.Errorf("|D| = %d above %d.\n", -D, MaxDiscriminant)
+	}
+	// 4p - t² = |D|v²
+	rest := new(big.Int).Sub(new(big.Int).Lsh(p, 2), new(big.Int).Mul(t, t))
+	if rest.Sign() <= 0 {
+		return nil, fmt.Errorf("t = %s is out of the Hasse interval for p %s.\n", t, p)
+	}
+	v2, r := new(big.Int).QuoRem(rest, big.NewInt(-D), new(big.Int))
+	v := new(big.Int).Sqrt(v2)
+	if r.Sign() != 0 || new(big.Int).Mul(v, v).Cmp(v2) != 0 {
+		return nil, fmt.Errorf("4p - t² = %s is not |D|v² for D = %d.\n", rest, D)
+	}
+
+	H, err := HilbertClassPolynomial(D)
+	if err != nil {
+		return nil, err
+	}
+	N := new(big.Int).Sub(new(big.Int).Add(p, big.NewInt(1)), t)
+	var others []*big.Int
+	for _, trace := range Traces(D, t, v) {
+		if trace.Cmp(t) != 0 {
+			others = append(others, new(big.Int).Sub(new(big.Int).Add(p, big.NewInt(1)), trace))
+		}
+	}
+
+	j1728 := new(big.Int).Mod(big.NewInt(1728), p)
+	for _, j := range polynom.NewPolynom(H, p).Roots() {
+		var curve *ec.EllipticCurve
+		switch {
+		case j.Sign() == 0:
+			if D != -3 {
+				continue
+			}
+			curve, err = randomTwist(p, N, others, func(c *big.Int) (*big.Int, *big.Int) {
+				return big.NewInt(0), c
+			})
+		case j.Cmp(j1728) == 0:
+			if D != -4 {
+				continue
+			}
+			curve, err = randomTwist(p, N, others, func(c *big.Int) (*big.Int, *big.Int) {
+				return c, big.NewInt(0)
+			})
+		default:
+			curve, err = quadraticTwist(p, j, N, others)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if curve != nil {
+			return &Curve{Curve: curve, D: D, J: j, T: new(big.Int).Set(t), N: N}, nil
+		}
+	}
+	return nil, fmt.Errorf("No curve of %s points found from the roots of H_%d mod %s.\n", N, D, p)
+}
+
+// CurveWithOrder Returns a curve mod p of N points, N in the Hasse interval: its CM discriminant D is the fundamental
+// discriminant of 4p - t² = |D|f², t = p + 1 - N, found by factoring. Fails if |D| is above MaxDiscriminant,
+// the Hilbert class polynomial being then out of reach.
+func CurveWithOrder(p, N *big.Int) (*Curve, error) {
+	t := new(big.Int).Sub(new(big.Int).Add(p, big.NewInt(1)), N)
+	rest := new(big.Int).Sub(new(big.Int).Lsh(p, 2), new(big.Int).Mul(t, t))
+	if rest.Sign() <= 0 {
+		return nil, fmt.Errorf("%s is out of the Hasse interval for p %s.\n", N, p)
+	}
+	factors, ok := utils.Factorize(rest)
+	if !ok {
+		return nil, fmt.Errorf("Could not factor 4p - t² = %s.\n", rest)
+	}
+	// square-free part s of 4p - t², D = -s or -4s
+	s := big.NewInt(1)
+	for _, pp := range factors {
+		if pp.E%2 == 1 {
+			s.Mul(s, pp.P)
+		}
+	}
+	if new(big.Int).And(s, big.NewInt(3)).Int64() != 3 {
+		s.Lsh(s, 2)
+	}
+	if !s.IsInt64() || s.Int64() > MaxDiscriminant {
+		return nil, fmt.Errorf("CM discriminant -%s of %s points above %d.\n", s, N, MaxDiscriminant)
+	}
+	return Construct(p, -s.Int64(), t)
+}
+
+// Generate Returns a curve mod p whose number of points satisfies accept (e.g. prime, or a small cofactor times
+// a prime), a nil accept taking any: the fundamental discriminants D are tried by increasing |D| <= maxAbsD,
+// each with the traces of Traces when 4p = t² + |D|v² has a solution.
+func Generate(p *big.Int, maxAbsD int64, accept func(N *big.Int) bool) (*Curve, error) {
+	if maxAbsD > MaxDiscriminant {
+		maxAbsD = MaxDiscriminant
+	}
+	for D := int64(-3); D >= -maxAbsD; D-- {
+		if !isFundamental(D) {
+			continue
+		}
+		t, v, ok := Cornacchia(D, p)
+		if !ok {
+			continue
+		}
+		for _, trace := range Traces(D, t, v) {
+			N := new(big.Int).Sub(new(big.Int).Add(p, big.NewInt(1)), trace)
+			if accept != nil && !accept(N) {
+				continue
+			}
+			return Construct(p, D, trace)
+		}
+	}
+	return nil, fmt.Errorf("No accepted curve mod %s with a discriminant of absolute value up to %d.\n", p, maxAbsD)
+}
+
+// quadraticTwist Returns the curve of j-invariant j (≠ 0, 1728) and of N points, y² = x³ + 3kx + 2k,
+// k = j / (1728 - j), or its twist by the smallest non-residue c, or nil if neither has N points.
+func quadraticTwist(p, j, N *big.Int, others []*big.Int) (*ec.EllipticCurve, error) {
+	k := new(big.Int).Sub(big.NewInt(1728), j)
+	k.ModInverse(k.Mod(k, p), p)
+	k.Mul(k, j).Mod(k, p)
+	a := new(big.Int).Mul(k, big.NewInt(3))
+	b := new(big.Int).Mul(k, big.NewInt(2))
+
+	c := big.NewInt(2)
+	for big.Jacobi(c, p) != -1 {
+		c.Add(c, big.NewInt(1))
+	}
+	c2 := new(big.Int).Mul(c, c)
+	c3 := new(big.Int).Mul(c2, c)
+	twist := [][2]*big.Int{
+		{a, b},
+		{new(big.Int).Mul(a, c2), new(big.Int).Mul(b, c3)},
+	}
+	for _, ab := range twist {
+		curve, err := ec.NewEllipticCurve(new(big.Int).Mod(ab[0], p), new(big.Int).Mod(ab[1], p), p)
+		if err != nil {
+			return nil, err
+		}
+		if orderIs(curve, N, others) {
+			return curve, nil
+		}
+	}
+	return nil, nil
+}
+
+// randomTwist Returns the curve of coefficients coeffs(c), c drawn at random, having N points, or nil if none was
+// found after twistAttempts draws.
+func randomTwist(p, N *big.Int, others []*big.Int, coeffs func(c *big.Int) (*big.Int, *big.Int)) (*ec.EllipticCurve, error) {
+	for i := 0; i < twistAttempts; i++ {
+		c, err := rand.Int(rand.Reader, new(big.Int).Sub(p, big.NewInt(1)))
+		if err != nil {
+			return nil, err
+		}
+		a, b := coeffs(c.Add(c, big.NewInt(1)))
+		curve, err := ec.NewEllipticCurve(a, b, p)
+		if err != nil {
+			return nil, err
+		}
+		if orderIs(curve, N, others) {
+			return curve, nil
+		}
+	}
+	return nil, nil
+}
+
+// orderIs True iff the curve, known to have one of the numbers of points N and others, has N points:
+// [N]P = O for orderDraws random points P, while each other candidate M leaves some [M]P ≠ O.
+func orderIs(curve *ec.EllipticCurve, N *big.Int, others []*big.Int) bool {
+	ruledOut := make([]bool, len(others))
+	for i := 0; i < orderDraws; i++ {
+		P := curve.RandomPoint()
+		if P == nil {
+			return false
+		}
+		Q, err := curve.MultiplyPointByScalar(P, new(big.Int).Set(N))
+		if err != nil || Q != nil {
+			return false
+		}
+		for m, M := range others {
+			if ruledOut[m] || M.Cmp(N) == 0 {
+				continue
+			}
+			Q, err := curve.MultiplyPointByScalar(P, new(big.Int).Set(M))
+			ruledOut[m] = err == nil && Q != nil
+		}
+	}
+	for m, M := range others {
+		if !ruledOut[m] && M.Cmp(N) != 0 {
+			return false
+		}
+	}
+	return true
+}
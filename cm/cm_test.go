@@ -0,0 +1,79 @@
+package cm
+
+import (
+	"goschoof/ec"
+	"math/big"
+	"testing"
+)
+
+// TestConstructOrders checks the number of points of the curves built for all the traces of the small |D|
+func TestConstructOrders(t *testing.T) {
+	for _, p := range []int64{101, 103, 1009, 10007, 65537} {
+		P := big.NewInt(p)
+		built := 0
+		for D := int64(-3); D >= -40; D-- {
+			if !isFundamental(D) {
+				continue
+			}
+			tr, v, ok := Cornacchia(D, P)
+			if !ok {
+				continue
+			}
+			for _, trace := range Traces(D, tr, v) {
+				c, err := Construct(P, D, trace)
+				if err != nil {
+					t.Fatalf("Construct(%d, %d, %s): %v", p, D, trace, err)
+				}
+				N, err := ec.CountPointsNaive(c.Curve)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := new(big.Int).Sub(big.NewInt(p+1), trace)
+				if N.Cmp(want) != 0 || c.N.Cmp(want) != 0 {
+					t.Errorf("Construct(%d, %d, %s) = %s has %s points, want %s", p, D, trace, c, N, want)
+				}
+				built++
+			}
+		}
+		if built == 0 {
+			t.Errorf("no curve built mod %d", p)
+		}
+	}
+}
+
+func TestCurveWithOrder(t *testing.T) {
+	p := big.NewInt(10007)
+	for _, n := range []int64{10007 + 1 - 50, 10007 + 1, 10007 + 1 + 199} {
+		c, err := CurveWithOrder(p, big.NewInt(n))
+		if err != nil {
+			t.Fatalf("CurveWithOrder(10007, %d): %v", n, err)
+		}
+		if N, _ := ec.CountPointsNaive(c.Curve); N.Int64() != n {
+			t.Errorf("CurveWithOrder(10007, %d) = %s has %s points", n, c, N)
+		}
+	}
+	if _, err := CurveWithOrder(p, big.NewInt(10007+1+201)); err == nil {
+		t.Error("CurveWithOrder accepted an order out of the Hasse interval")
+	}
+}
+
+func TestConstructErrors(t *testing.T) {
+	p := big.NewInt(101)
+	cases := []struct {
+		p *big.Int
+		D int64
+		t int64
+	}{
+		{p, 0, 2},   // not a discriminant, used to divide by zero
+		{p, 1, 2},   // positive
+		{p, -5, 2},  // 3 mod 4
+		{p, -3, 3},  // 4p - t² = 395 is not 3v²
+		{p, -3, 21}, // out of the Hasse interval
+		{big.NewInt(100), -3, 2},
+	}
+	for _, c := range cases {
+		if _, err := Construct(c.p, c.D, big.NewInt(c.t)); err == nil {
+			t.Errorf("Construct(%s, %d, %d) accepted", c.p, c.D, c.t)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cm
+
+import (
+	"math/big"
+)
+
+// Cornacchia Returns (t, v) such as 4p = t² + |D|v², p being an odd prime and D < 0 a discriminant
+// (D = 0 or 1 mod 4) with |D| < 4p, by the modified Cornacchia algorithm: from x0² = D mod p, x0 = D mod 2,
+// the Euclidean algorithm on (2p, x0) is run until the remainder b falls below 2·sqrt(p), then t = b if
+// (4p - b²) / |D| is a square v². ok is false when there is no solution, e.g. when D is not a square mod p.
+func Cornacchia(D int64, p *big.Int) (t, v *big.Int, ok bool) {
+	absD := big.NewInt(-D)
+	fourP := new(big.Int).Lsh(p, 2)
+	if !isDiscriminant(D) || absD.Cmp(fourP) >= 0 {
+		return nil, nil, false
+	}
+	d := big.NewInt(D)
+	if absD.Cmp(p) == 0 {
+		// p | D: 4p = t² + |D|v² forces t = 0 and |D| = p, v = 2 (a supersingular trace), out of the algorithm
+		return big.NewInt(0), big.NewInt(2), true
+	}
+	if big.Jacobi(new(big.Int).Mod(d, p), p) != 1 {
+		return nil, nil, false
+	}
+
+	x0 := new(big.Int).ModSqrt(new(big.Int).Mod(d, p), p)
+	if x0.Bit(0) != uint(D&1) {
+		x0.Sub(p, x0)
+	}
+	a := new(big.Int).Lsh(p, 1)
+	b := x0
+	limit := new(big.Int).Sqrt(fourP) // floor(2·sqrt(p))
+	for b.Cmp(limit) > 0 {
+		a, b = b, new(big.Int).Mod(a, b)
+	}
+
+	rest := new(big.Int).Sub(fourP, new(big.Int).Mul(b, b))
+	c, r := new(big.Int).QuoRem(rest, absD, new(big.Int))
+	if r.Sign() != 0 {
+		return nil, nil, false
+	}
+	v = new(big.Int).Sqrt(c)
+	if new(big.Int).Mul(v, v).Cmp(c) != 0 {
+		return nil, nil, false
+	}
+	return b, v, true
+}
+
+// isFundamental True iff D < 0 is a fundamental discriminant: D = 1 mod 4 square-free,
+// or D = 4m with m = 2 or 3 mod 4 square-free
+func isFundamental(D int64) bool {
+	m := -D
+	switch {
+	case m%4 == 3:
+		return squareFree(m)
+	case m%4 == 0:
+		q := m / 4
+		return (q%4 == 1 || q%4 == 2) && squareFree(q)
+	}
+	return false
+}
+
+// squareFree True iff no square of a prime divides n > 0
+func squareFree(n int64) bool {
+	for f := int64(2); f*f <= n; f++ {
+		if n%(f*f) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isDiscriminant True iff D < 0 and D = 0 or 1 mod 4
+func isDiscriminant(D int64) bool {
+	return D < 0 && (D%4 == 0 || D%4 == -3)
+}
@@ -0,0 +1,45 @@
+package cm
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestCornacchia compares Cornacchia with an exhaustive search of 4p = t² + |D|v², |D| = p included
+func TestCornacchia(t *testing.T) {
+	for _, p := range []int64{5, 7, 13, 101, 103, 1009, 10007} {
+		P := big.NewInt(p)
+		for D := int64(-3); D >= -200; D-- {
+			if !isFundamental(D) || -D >= 4*p { // |D| < 4p
+				continue
+			}
+			exists := false
+			for v := int64(1); -D*v*v <= 4*p && !exists; v++ {
+				rest := 4*p + D*v*v
+				s := new(big.Int).Sqrt(big.NewInt(rest)).Int64()
+				exists = s*s == rest
+			}
+			tr, v, ok := Cornacchia(D, P)
+			if ok != exists {
+				t.Errorf("Cornacchia(%d, %d): ok = %t, a solution exists: %t", D, p, ok, exists)
+				continue
+			}
+			if !ok {
+				continue
+			}
+			sum := new(big.Int).Mul(tr, tr)
+			sum.Add(sum, new(big.Int).Mul(big.NewInt(-D), new(big.Int).Mul(v, v)))
+			if sum.Cmp(big.NewInt(4*p)) != 0 {
+				t.Errorf("Cornacchia(%d, %d) = (%s, %s), t² + |D|v² = %s != 4p", D, p, tr, v, sum)
+			}
+		}
+	}
+
+	// -7 is not a square mod 5, 3 = 0 mod 4 is not a discriminant
+	if _, _, ok := Cornacchia(-7, big.NewInt(5)); ok {
+		t.Error("Cornacchia(-7, 5) found a solution, -7 not being a square mod 5")
+	}
+	if _, _, ok := Cornacchia(-5, big.NewInt(101)); ok {
+		t.Error("Cornacchia accepted the non discriminant -5")
+	}
+}
@@ -0,0 +1,276 @@
+package cm
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Form The binary quadratic form ax² + bxy + cy², of discriminant b² - 4ac.
+type Form struct {
+	A, B, C int64
+}
+
+// ReducedForms Returns the primitive reduced forms of discriminant D < 0: |b| <= a <= c, b >= 0 when |b| = a or a = c,
+// gcd(a, b, c) = 1. There is one per class of the class group of discriminant D, their number being the class number.
+func ReducedForms(D int64) []Form {
+	var forms []Form
+	for a := int64(1); 3*a*a <= -D; a++ {
+		for b := -a + 1; b <= a; b++ {
+			num := b*b - D
+			if num%(4*a) != 0 {
+				continue
+			}
+			c := num / (4 * a)
+			if c < a || (b < 0 && a == c) || gcd(gcd(a, abs(b)), c) != 1 {
+				continue
+			}
+			forms = append(forms, Form{A: a, B: b, C: c})
+		}
+	}
+	return forms
+}
+
+// HilbertClassPolynomial Returns the coefficients (by increasing degree) of the Hilbert class polynomial
+// H_D(X) = prod(X - j(τ)), over the reduced forms (a, b, c) of discriminant D (see ReducedForms), τ = (-b + sqrt(D)) / 2a.
+// H_D has integer coefficients; they are found by computing the j(τ) with complex floating-point arithmetic
+// (see jInvariant), at a precision bounded from the size of the j(τ), then rounding. Fails if the rounding is not
+// clear-cut (the precision being then too low).
+func HilbertClassPolynomial(D int64) ([]*big.Int, error) {
+	if !isDiscriminant(D) {
+		return nil, fmt.Errorf("%d is not a negative discriminant.\n", D)
+	}
+	forms := ReducedForms(D)
+
+	// |j(τ)| ~ exp(π·sqrt(|D|)/a): the coefficients have at most sum(log2(|j(τ)| + 1)) + h bits
+	bits := float64(len(forms))
+	for _, f := range forms {
+		bits += math.Pi*math.Sqrt(float64(-D))/float64(f.A)/math.Ln2 + 11
+	}
+	prec := uint(bits) + 64
+
+	// H_D = prod(X - j), coefficients by increasing degree
+	H := []complexFloat{newComplex(prec, 1)}
+	for _, f := range forms {
+		j := jInvariant(f, D, prec)
+		next := make([]complexFloat, len(H)+1)
+		for i := range next {
+			next[i] = newComplex(prec, 0)
+		}
+		for i, c := range H {
+			next[i+1] = next[i+1].add(c)
+			next[i] = next[i].sub(c.mul(j))
+		}
+		H = next
+	}
+
+	coeffs := make([]*big.Int, len(H))
+	half := new(big.Float).SetFloat64(0.5)
+	tolerance := new(big.Float).SetFloat64(1.0 / 1024)
+	for i, c := range H {
+		rounded := new(big.Float).SetPrec(prec).Add(c.re, half)
+		if c.re.Sign() < 0 {
+			rounded.Sub(c.re, half)
+		}
+		coeffs[i], _ = rounded.Int(nil)
+		err := new(big.Float).Sub(c.re, new(big.Float).SetInt(coeffs[i]))
+		if err.Abs(err).Cmp(tolerance) > 0 || new(big.Float).Abs(c.im).Cmp(tolerance) > 0 {
+			return nil, fmt.Errorf("Coefficient %d of H_%d is not close to an integer: %s + %si.\n",
+				i, D, c.re.Text('g', 20), c.im.Text('g', 20))
+		}
+	}
+	return coeffs, nil
+}
+
+// jInvariant Returns j(τ) for τ = (-b + sqrt(D)) / 2a: with q = exp(2iπτ) = exp(-π·sqrt(|D|)/a)·exp(-iπb/a),
+// f = Δ(2τ)/Δ(τ) = q·prod(1 + q^n)^24 (Δ = q·prod(1 - q^n)^24), and j = (256f + 1)³ / f.
+// The product stops when |q^n| < 2^-prec, |q| <= exp(-π·sqrt(3)) for a reduced form.
+func jInvariant(f Form, D int64, prec uint) complexFloat {
+	pi := piFloat(prec)
+	// |q| = exp(-π·sqrt(|D|) / a)
+	modulus := new(big.Float).SetPrec(prec).Sqrt(new(big.Float).SetPrec(prec).SetInt64(-D))
+	modulus.Mul(modulus, pi)
+	modulus.Quo(modulus, new(big.Float).SetPrec(prec).SetInt64(f.A))
+	modulus = expFloat(modulus.Neg(modulus), prec)
+	// arg(q) = -πb / a
+	angle := new(big.Float).SetPrec(prec).Mul(pi, new(big.Float).SetPrec(prec).SetInt64(-f.B))
+	angle.Quo(angle, new(big.Float).SetPrec(prec).SetInt64(f.A))
+	cos, sin := cosSin(angle, prec)
+	q := complexFloat{
+		re: new(big.Float).SetPrec(prec).Mul(modulus, cos),
+		im: new(big.Float).SetPrec(prec).Mul(modulus, sin),
+	}
+
+	one := newComplex(prec, 1)
+	product := newComplex(prec, 1)
+	qn := q
+	rn := new(big.Float).SetPrec(prec).Set(modulus) // |q^n|
+	epsilon := new(big.Float).SetMantExp(big.NewFloat(1), -int(prec))
+	for rn.Cmp(epsilon) > 0 {
+		product = product.mul(one.add(qn))
+		qn = qn.mul(q)
+		rn.Mul(rn, modulus)
+	}
+	// product^24 = product^8·product^16
+	p2 := product.mul(product)
+	p4 := p2.mul(p2)
+	p8 := p4.mul(p4)
+	p16 := p8.mul(p8)
+	eta := q.mul(p8.mul(p16))
+
+	num := eta.scale(256).add(one)
+	return num.mul(num).mul(num).div(eta)
+}
+
+// complexFloat A complex number with big.Float parts
+type complexFloat struct {
+	re, im *big.Float
+}
+
+func newComplex(prec uint, re int64) complexFloat {
+	return complexFloat{re: new(big.Float).SetPrec(prec).SetInt64(re), im: new(big.Float).SetPrec(prec)}
+}
+
+func (z complexFloat) prec() uint {
+	return z.re.Prec()
+}
+
+func (z complexFloat) add(w complexFloat) complexFloat {
+	return complexFloat{
+		re: new(big.Float).SetPrec(z.prec()).Add(z.re, w.re),
+		im: new(big.Float).SetPrec(z.prec()).Add(z.im, w.im),
+	}
+}
+
+func (z complexFloat) sub(w complexFloat) complexFloat {
+	return complexFloat{
+		re: new(big.Float).SetPrec(z.prec()).Sub(z.re, w.re),
+		im: new(big.Float).SetPrec(z.prec()).Sub(z.im, w.im),
+	}
+}
+
+// mul (a + ib)(c + id) = ac - bd + i(ad + bc)
+func (z complexFloat) mul(w complexFloat) complexFloat {
+	prec := z.prec()
+	ac := new(big.Float).SetPrec(prec).Mul(z.re, w.re)
+	bd := new(big.Float).SetPrec(prec).Mul(z.im, w.im)
+	ad := new(big.Float).SetPrec(prec).Mul(z.re, w.im)
+	bc := new(big.Float).SetPrec(prec).Mul(z.im, w.re)
+	return complexFloat{re: ac.Sub(ac, bd), im: ad.Add(ad, bc)}
+}
+
+// div z / w = z·conj(w) / |w|²
+func (z complexFloat) div(w complexFloat) complexFloat {
+	prec := z.prec()
+	norm := new(big.Float).SetPrec(prec).Mul(w.re, w.re)
+	norm.Add(norm, new(big.Float).SetPrec(prec).Mul(w.im, w.im))
+	conj := complexFloat{re: w.re, im: new(big.Float).SetPrec(prec).Neg(w.im)}
+	res := z.mul(conj)
+	res.re.Quo(res.re, norm)
+	res.im.Quo(res.im, norm)
+	return res
+}
+
+func (z complexFloat) scale(k int64) complexFloat {
+	f := new(big.Float).SetPrec(z.prec()).SetInt64(k)
+	return complexFloat{
+		re: new(big.Float).SetPrec(z.prec()).Mul(z.re, f),
+		im: new(big.Float).SetPrec(z.prec()).Mul(z.im, f),
+	}
+}
+
+// piFloat Returns π by Machin's formula, π = 16·arctan(1/5) - 4·arctan(1/239)
+func piFloat(prec uint) *big.Float {
+	work := prec + 16
+	pi := arctanInverse(5, work)
+	pi.Mul(pi, new(big.Float).SetInt64(16))
+	pi.Sub(pi, new(big.Float).SetPrec(work).Mul(arctanInverse(239, work), new(big.Float).SetInt64(4)))
+	return pi.SetPrec(prec)
+}
+
+// arctanInverse Returns arctan(1/k) = sum((-1)^n / ((2n + 1)·k^(2n+1)))
+func arctanInverse(k int64, prec uint) *big.Float {
+	sum := new(big.Float).SetPrec(prec)
+	power := new(big.Float).SetPrec(prec).Quo(big.NewFloat(1), new(big.Float).SetInt64(k)) // 1/k^(2n+1)
+	k2 := new(big.Float).SetInt64(k * k)
+	epsilon := new(big.Float).SetMantExp(big.NewFloat(1), -int(prec))
+	for n := int64(0); power.Cmp(epsilon) > 0; n++ {
+		term := new(big.Float).SetPrec(prec).Quo(power, new(big.Float).SetInt64(2*n+1))
+		if n%2 == 0 {
+			sum.Add(sum, term)
+		} else {
+			sum.Sub(sum, term)
+		}
+		power.Quo(power, k2)
+	}
+	return sum
+}
+
+// expFloat Returns exp(x): exp(x / 2^s) by its Taylor series, |x / 2^s| < 1, then squared s times
+func expFloat(x *big.Float, prec uint) *big.Float {
+	s := x.MantExp(nil)
+	if s < 0 {
+		s = 0
+	}
+	work := prec + uint(s) + 32
+	y := new(big.Float).SetPrec(work).SetMantExp(x, -s)
+
+	sum := new(big.Float).SetPrec(work).SetInt64(1)
+	term := new(big.Float).SetPrec(work).SetInt64(1)
+	epsilon := new(big.Float).SetMantExp(big.NewFloat(1), -int(work))
+	for n := int64(1); ; n++ {
+		term.Mul(term, y)
+		term.Quo(term, new(big.Float).SetInt64(n))
+		if new(big.Float).Abs(term).Cmp(epsilon) < 0 {
+			break
+		}
+		sum.Add(sum, term)
+	}
+	for i := 0; i < s; i++ {
+		sum.Mul(sum, sum)
+	}
+	return sum.SetPrec(prec)
+}
+
+// cosSin Returns cos(x) and sin(x) by their Taylor series, for |x| <= π
+func cosSin(x *big.Float, prec uint) (*big.Float, *big.Float) {
+	work := prec + 16
+	cos := new(big.Float).SetPrec(work).SetInt64(1)
+	sin := new(big.Float).SetPrec(work).Set(x)
+	term := new(big.Float).SetPrec(work).SetInt64(1) // x^n / n!
+	epsilon := new(big.Float).SetMantExp(big.NewFloat(1), -int(work))
+	for n := int64(1); ; n++ {
+		term.Mul(term, x)
+		term.Quo(term, new(big.Float).SetInt64(n))
+		if n > 2 && new(big.Float).Abs(term).Cmp(epsilon) < 0 {
+			break
+		}
+		switch n % 4 {
+		case 2:
+			cos.Sub(cos, term)
+		case 3:
+			sin.Sub(sin, term)
+		case 0:
+			cos.Add(cos, term)
+		case 1:
+			if n > 1 {
+				sin.Add(sin, term)
+			}
+		}
+	}
+	return cos.SetPrec(prec), sin.SetPrec(prec)
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func abs(a int64) int64 {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,55 @@
+package cm
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestHilbertClassPolynomial(t *testing.T) {
+	cases := []struct {
+		D    int64
+		want []string // by increasing degree
+	}{
+		{-3, []string{"0", "1"}},
+		{-4, []string{"-1728", "1"}},
+		{-7, []string{"3375", "1"}},
+		{-8, []string{"-8000", "1"}},
+		{-15, []string{"-121287375", "191025", "1"}},
+		{-20, []string{"-681472000", "-1264000", "1"}},
+		{-23, []string{"12771880859375", "-5151296875", "3491750", "1"}},
+	}
+	for _, c := range cases {
+		H, err := HilbertClassPolynomial(c.D)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(H) != len(c.want) {
+			t.Errorf("H_%d = %v, want %v", c.D, H, c.want)
+			continue
+		}
+		for i, s := range c.want {
+			if want, _ := new(big.Int).SetString(s, 10); H[i].Cmp(want) != 0 {
+				t.Errorf("H_%d = %v, want %v", c.D, H, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestHilbertClassPolynomialDegree(t *testing.T) {
+	// the degree is the class number h(D), the number of reduced forms
+	for _, c := range []struct{ D, h int64 }{{-47, 5}, {-71, 7}, {-199, 9}, {-479, 25}} {
+		H, err := HilbertClassPolynomial(c.D)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int64(len(H)-1) != c.h || int64(len(ReducedForms(c.D))) != c.h || H[c.h].Int64() != 1 {
+			t.Errorf("H_%d of degree %d, want h = %d", c.D, len(H)-1, c.h)
+		}
+	}
+	for _, D := range []int64{0, 1, -1, -2, -5} {
+		if _, err := HilbertClassPolynomial(D); err == nil {
+			t.Errorf("HilbertClassPolynomial(%d) accepted a non discriminant", D)
+		}
+	}
+}
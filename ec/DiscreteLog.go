@@ -0,0 +1,436 @@
+package ec
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"goschoof/utils"
+	"math/big"
+	"runtime"
+	"sync"
+)
+
+// maxBSGSOrder the largest order handled by the baby-step giant-step search (about 2^20 points stored)
+const maxBSGSOrder = 1 << 40
+
+// rhoPartitions number of steps [c_j]P + [d_j]Q of the r-adding walk of PollardRho
+const rhoPartitions = 32
+
+// rhoMaxDbits bound on the distinguished bits of PollardRho, keeping the walk length 20·2^dbits in an int64
+const rhoMaxDbits = 56
+
+// rhoMaxDistinguished how many distinguished points PollardRho collects before giving up, about 2^8 being expected
+const rhoMaxDistinguished = 1 << 16
+
+// kangarooAttempts how many jump functions Kangaroo tries before giving up
+const kangarooAttempts = 8
+
+// ErrNotInSubgroup Returned when Q is not a multiple of P, so that no discrete logarithm exists.
+var ErrNotInSubgroup = errors.New("Q is not in the subgroup generated by P.\n")
+
+// primeLog Solves [x]A = B in the subgroup of prime order q, ok being false when B is not in <A>
+type primeLog func(A, B *Point, q *big.Int) (*big.Int, bool)
+
+// BSGS Returns x in [0, n) such as [x]P = Q with the baby-step giant-step algorithm, n being the order of P
+// (or any multiple of it). O(sqrt(n)) time and memory, hence n is limited to 2^40.
+func (ec *EllipticCurve) BSGS(P, Q *Point, n *big.Int) (*big.Int, error) {
+	if err := ec.checkLogInput(P, Q, n); err != nil {
+		return nil, err
+	}
+	if n.Cmp(big.NewInt(maxBSGSOrder)) > 0 {
+		return nil, fmt.Errorf("Order %s too big for the baby-step giant-step search.\n", n)
+	}
+	x, ok := ec.bsgs(P, Q, n)
+	if !ok {
+		return nil, ErrNotInSubgroup
+	}
+	return x, nil
+}
+
+// rhoPoint A point R = [a]P + [b]Q of the walk of PollardRho
+type rhoPoint struct {
+	R *Point
+	a *big.Int
+	b *big.Int
+}
+
+// rhoWalk The r-adding walk R -> R + M_j of PollardRho, j depending on the x coordinate of R,
+// with M_j = [c_j]P + [d_j]Q chosen at random. A point is distinguished when the dbits lowest bits of its x are 0.
+type rhoWalk struct {
+	P, Q  *Point
+	n     *big.Int
+	steps []rhoPoint
+	dbits uint
+}
+
+// PollardRho Returns x in [0, n) such as [x]P = Q, P being of prime order n, with Pollard's rho algorithm
+// in the parallel version of van Oorschot and Wiener: workers goroutines (runtime.NumCPU() if <= 0) run random walks
+// R = [a]P + [b]Q until they meet a distinguished point, which is sent to a shared table; two walks reaching the same
+// point give a + bx = a' + b'x mod n. O(sqrt(n)) time, little memory.
+// ErrNotInSubgroup is returned when [n]Q != O. Q may still not be in <P> when the group is not cyclic: the search then
+// stops with an error after rhoMaxDistinguished distinguished points, far more than the 2^8 expected.
+func (ec *EllipticCurve) PollardRho(ctx context.Context, P, Q *Point, n *big.Int, workers int) (*big.Int, error) {
+	if err := ec.checkLogInput(P, Q, n); err != nil {
+		return nil, err
+	}
+	if Q == nil {
+		return big.NewInt(0), nil
+	}
+	if ec.mul(Q, n) != nil {
+		return nil, ErrNotInSubgroup
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	w := &rhoWalk{P: P, Q: Q, n: n}
+	// walks of length about 2^dbits, so that about 2^8 distinguished points are expected
+	if bits := n.BitLen()/2 - 8; bits > 0 {
+		w.dbits = uint(min(bits, rhoMaxDbits))
+	}
+	for j := 0; j < rhoPartitions; j++ {
+		w.steps = append(w.steps, ec.rhoStart(w))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	found := make(chan rhoPoint)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ec.rhoWorker(ctx, w, found)
+		}()
+	}
+
+	seen := make(map[string]rhoPoint)
+	for received := 0; received < rhoMaxDistinguished; received++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case d := <-found:
+			key := d.R.String()
+			other, collision := seen[key]
+			if !collision {
+				seen[key] = d
+				continue
+			}
+			// a + bx = a' + b'x  =>  x = (a - a') / (b' - b)
+			den := new(big.Int).Sub(other.b, d.b)
+			den.Mod(den, n)
+			inv := new(big.Int).ModInverse(den, n)
+			if inv == nil {
+				continue // same walk, or n not prime
+			}
+			x := new(big.Int).Sub(d.a, other.a)
+			x.Mul(x, inv)
+			x.Mod(x, n)
+			if ec.mul(P, x).Equals(Q) {
+				return x, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("No logarithm found after %d distinguished points, Q is likely not in <P>.\n", rhoMaxDistinguished)
+}
+
+// rhoWorker Runs walks from random points, sending their distinguished point to found, until ctx is done.
+// Walks longer than 20·2^dbits are abandoned, being likely stuck in a cycle.
+func (ec *EllipticCurve) rhoWorker(ctx context.Context, w *rhoWalk, found chan<- rhoPoint) {
+	maxLength := int64(20) << w.dbits
+	for {
+		cur := ec.rhoStart(w)
+		for i := int64(0); i < maxLength; i++ {
+			if i%1024 == 0 && ctx.Err() != nil {
+				return
+			}
+			if w.distinguished(cur.R) {
+				select {
+				case found <- cur:
+				case <-ctx.Done():
+					return
+				}
+				break
+			}
+			step := w.steps[w.index(cur.R)]
+			a := new(big.Int).Add(cur.a, step.a)
+			b := new(big.Int).Add(cur.b, step.b)
+			cur = rhoPoint{R: ec.add(cur.R, step.R), a: a.Mod(a, w.n), b: b.Mod(b, w.n)}
+		}
+	}
+}
+
+// rhoStart Returns [a]P + [b]Q for random a, b in [0, n)
+func (ec *EllipticCurve) rhoStart(w *rhoWalk) rhoPoint {
+	a := randomBelow(w.n)
+	b := randomBelow(w.n)
+	return rhoPoint{R: ec.add(ec.mul(w.P, a), ec.mul(w.Q, b)), a: a, b: b}
+}
+
+// index Returns the step taken from R, from the bits of its x above the distinguishing ones
+func (w *rhoWalk) index(R *Point) int {
+	if R == nil {
+		return 0
+	}
+	return int(new(big.Int).Rsh(R.x, w.dbits).Uint64() % rhoPartitions)
+}
+
+// distinguished True iff the dbits lowest bits of the x of R are 0 (the omega being distinguished)
+func (w *rhoWalk) distinguished(R *Point) bool {
+	if R == nil {
+		return true
+	}
+	for i := 0; i < int(w.dbits); i++ {
+		if R.x.Bit(i) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Kangaroo Returns x in [a, b] such as [x]P = Q with Pollard's kangaroo (lambda) algorithm, suited to a logarithm
+// known to lie in an interval: O(sqrt(b - a)) time and constant memory, whatever the order of P, which must exceed 2(b - a)
+// (the kangaroos could otherwise meet a lap apart).
+// A tame kangaroo jumps from [b]P and sets a trap where it stops; a wild one jumps from Q with the same jumps
+// (powers of 2 of mean about sqrt(b - a) / 2, chosen by the x of the current point) until it falls in the trap,
+// or is past it. The search is retried with other jump functions before failing.
+func (ec *EllipticCurve) Kangaroo(ctx context.Context, P, Q *Point, a, b *big.Int) (*big.Int, error) {
+	if P == nil || !ec.PointIsOnCurve(P) || !ec.PointIsOnCurve(Q) {
+		return nil, fmt.Errorf("Given points must be on the curve, P being different from O.\n")
+	}
+	if a.Sign() < 0 || a.Cmp(b) > 0 {
+		return nil, fmt.Errorf("Invalid interval [%s, %s].\n", a, b)
+	}
+	width := new(big.Int).Sub(b, a)
+	if width.BitLen() > 100 {
+		return nil, fmt.Errorf("Interval [%s, %s] too wide for the kangaroo search.\n", a, b)
+	}
+
+	// small interval: every candidate is tried
+	if width.Cmp(big.NewInt(1024)) < 0 {
+		R := ec.mul(P, a)
+		for x := new(big.Int).Set(a); x.Cmp(b) <= 0; x.Add(x, big.NewInt(1)) {
+			if R.Equals(Q) {
+				return x, nil
+			}
+			R = ec.add(R, P)
+		}
+		return nil, ErrNotInSubgroup
+	}
+
+	// jumps 2^0 .. 2^(k-1), of mean (2^k - 1) / k >= sqrt(width) / 2
+	mean := new(big.Int).Sqrt(width)
+	mean.Rsh(mean, 1)
+	k := 1
+	for big.NewInt(int64(1<<k-1)/int64(k)).Cmp(mean) < 0 {
+		k++
+	}
+	jumps := make([]*Point, k)
+	for i := range jumps {
+		jumps[i] = ec.mul(P, new(big.Int).Lsh(big.NewInt(1), uint(i)))
+	}
+	// the tame kangaroo travels about width
+	tameJumps := 4 * mean.Int64()
+
+	for seed := uint64(0); seed < kangarooAttempts; seed++ {
+		hop := func(R *Point) int {
+			if R == nil {
+				return 0
+			}
+			return int(((R.x.Uint64() + seed) * 0x9E3779B97F4A7C15 >> 32) % uint64(k))
+		}
+
+		// tame kangaroo, from [b]P
+		trap := ec.mul(P, b)
+		tame := big.NewInt(0)
+		for i := int64(0); i < tameJumps; i++ {
+			if i%1024 == 0 && ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			j := hop(trap)
+			trap = ec.add(trap, jumps[j])
+			tame.Add(tame, new(big.Int).Lsh(big.NewInt(1), uint(j)))
+		}
+
+		// wild kangaroo, from Q = [x]P: the trap [b + tame]P is at most width + tame ahead
+		limit := new(big.Int).Add(width, tame)
+		R := Q
+		wild := big.NewInt(0)
+		for i := 0; wild.Cmp(limit) <= 0; i++ {
+			if i%1024 == 0 && ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if R.Equals(trap) {
+				// x + wild = b + tame
+				x := new(big.Int).Add(b, tame)
+				x.Sub(x, wild)
+				if x.Cmp(a) >= 0 && x.Cmp(b) <= 0 {
+					return x, nil
+				}
+				break
+			}
+			j := hop(R)
+			R = ec.add(R, jumps[j])
+			wild.Add(wild, new(big.Int).Lsh(big.NewInt(1), uint(j)))
+		}
+	}
+	return nil, fmt.Errorf("No logarithm found in [%s, %s].\n", a, b)
+}
+
+// PohligHellman Returns the discrete logarithm x of Q in base P, with the number of points of the curve
+// computed by counter (e.g. schoof.CountPoints, a nil counter meaning CountPointsNaive), see DiscreteLog.
+func (ec *EllipticCurve) PohligHellman(ctx context.Context, P, Q *Point, counter PointCounter) (*big.Int, error) {
+	if counter == nil {
+		counter = CountPointsNaive
+	}
+	N, err := counter(ec)
+	if err != nil {
+		return nil, err
+	}
+	return ec.DiscreteLog(ctx, P, Q, N)
+}
+
+// DiscreteLog Returns x in [0, n) such as [x]P = Q, n being the order of P, N the number of points of the curve
+// (or any multiple of n). Pohlig-Hellman: N is factored, giving n, then x mod q^e is found for each q^e || n
+// digit by digit in the subgroup of order q, with BSGS for q up to 2^40 and PollardRho above; the x mod q^e are
+// combined by the CRT. The logarithm is thus as hard as for the largest prime factor of n.
+func (ec *EllipticCurve) DiscreteLog(ctx context.Context, P, Q *Point, N *big.Int) (*big.Int, error) {
+	if !ec.PointIsOnCurve(Q) {
+		return nil, fmt.Errorf("Given point q(%s,%s) is not on the curve.\n", Q.x, Q.y)
+	}
+	factors, ok := utils.Factorize(N)
+	if !ok {
+		return nil, fmt.Errorf("Could not factor %s.\n", N)
+	}
+	n, err := ec.OrderWithFactors(P, N, factors)
+	if err != nil {
+		return nil, err
+	}
+	if ec.mul(Q, n) != nil {
+		return nil, ErrNotInSubgroup
+	}
+
+	var solveErr error
+	solve := func(A, B *Point, q *big.Int) (*big.Int, bool) {
+		if q.Cmp(big.NewInt(maxBSGSOrder)) <= 0 {
+			return ec.bsgs(A, B, q)
+		}
+		x, err := ec.PollardRho(ctx, A, B, q, 0)
+		if err != nil {
+			solveErr = err
+			return nil, false
+		}
+		return x, true
+	}
+
+	x := big.NewInt(0)
+	M := big.NewInt(1) // x is known mod M
+	for _, pp := range factors {
+		e := valuation(n, pp.P)
+		if e == 0 {
+			continue
+		}
+		qe := new(big.Int).Exp(pp.P, big.NewInt(int64(e)), nil)
+		cofactor := new(big.Int).Quo(n, qe)
+		xq, ok := ec.dlogPrimePower(ec.mul(P, cofactor), ec.mul(Q, cofactor), pp.P, e, solve)
+		if !ok {
+			if solveErr != nil {
+				return nil, solveErr
+			}
+			return nil, ErrNotInSubgroup
+		}
+
+		// x + M·((xq - x)·M^-1 mod q^e) is x mod M and xq mod q^e
+		k := new(big.Int).Sub(xq, x)
+		k.Mul(k, new(big.Int).ModInverse(M, qe))
+		k.Mod(k, qe)
+		x.Add(x, k.Mul(k, M))
+		M.Mul(M, qe)
+	}
+
+	if !ec.mul(P, x).Equals(Q) {
+		return nil, ErrNotInSubgroup
+	}
+	return x, nil
+}
+
+// dlogPrimePower Returns x such as [x]G = H, G being of order q^e, with Pohlig-Hellman:
+// the base q digits of x are found one by one in the subgroup of order q by solve (e.g. bsgs).
+// ok is false when H is not in <G>.
+func (ec *EllipticCurve) dlogPrimePower(G, H *Point, q *big.Int, e int, solve primeLog) (*big.Int, bool) {
+	if e == 0 {
+		return big.NewInt(0), H == nil
+	}
+	x := big.NewInt(0)
+	qk := big.NewInt(1)                                                  // q^k
+	gamma := ec.mul(G, new(big.Int).Exp(q, big.NewInt(int64(e-1)), nil)) // of order q
+	for k := 0; k < e; k++ {
+		rest := ec.add(H, ec.neg(ec.mul(G, x))) // H - [x]G, in the subgroup of order q^(e-k)
+		Hk := ec.mul(rest, new(big.Int).Exp(q, big.NewInt(int64(e-1-k)), nil))
+		d, ok := solve(gamma, Hk, q)
+		if !ok {
+			return nil, false
+		}
+		x.Add(x, new(big.Int).Mul(d, qk))
+		qk.Mul(qk, q)
+	}
+	return x, ec.mul(G, x).Equals(H)
+}
+
+// bsgs Returns i in [0, q) such as [i]A = B, A being of order q, with the baby-step giant-step algorithm
+// (about sqrt(q) points stored). ok is false when B is not in <A>.
+func (ec *EllipticCurve) bsgs(A, B *Point, q *big.Int) (*big.Int, bool) {
+	if q.Cmp(big.NewInt(maxBSGSOrder)) > 0 {
+		panic(fmt.Sprintf("bsgs: order %s too big", q))
+	}
+	m := new(big.Int).Sqrt(q)
+	m.Add(m, big.NewInt(1))
+	steps := m.Int64()
+
+	// baby steps: [j]A for j < m
+	baby := make(map[string]int64, steps)
+	var current *Point
+	for j := int64(0); j < steps; j++ {
+		if _, found := baby[current.String()]; !found {
+			baby[current.String()] = j
+		}
+		current = ec.add(current, A)
+	}
+
+	// giant steps: B - [i*m]A
+	giant := ec.neg(ec.mul(A, m))
+	current = B
+	for i := int64(0); i <= steps; i++ {
+		if j, found := baby[current.String()]; found {
+			res := new(big.Int).Mul(big.NewInt(i), m)
+			res.Add(res, big.NewInt(j))
+			return res.Mod(res, q), true
+		}
+		current = ec.add(current, giant)
+	}
+	return nil, false
+}
+
+// checkLogInput Returns an error unless P and Q are on the curve, P != O, n > 0 and [n]P = O
+func (ec *EllipticCurve) checkLogInput(P, Q *Point, n *big.Int) error {
+	if P == nil || !ec.PointIsOnCurve(P) || !ec.PointIsOnCurve(Q) {
+		return fmt.Errorf("Given points must be on the curve, P being different from O.\n")
+	}
+	if n.Sign() <= 0 || ec.mul(P, n) != nil {
+		return fmt.Errorf("%s is not a multiple of the order of %s.\n", n, P)
+	}
+	return nil
+}
+
+// randomBelow Returns a uniformly chosen integer in [0, n)
+func randomBelow(n *big.Int) *big.Int {
+	r, err := rand.Int(rand.Reader, n)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
@@ -0,0 +1,215 @@
+package ec_test
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"goschoof/ec"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// multiply Returns [x]P, without modifying x
+func multiply(t *testing.T, curve *ec.EllipticCurve, P *ec.Point, x *big.Int) *ec.Point {
+	t.Helper()
+	R, err := curve.MultiplyPointByScalar(P, new(big.Int).Set(x))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return R
+}
+
+// randomMultiple Returns x in [0, n) at random and Q = [x]P
+func randomMultiple(t *testing.T, curve *ec.EllipticCurve, P *ec.Point, n *big.Int) (*big.Int, *ec.Point) {
+	t.Helper()
+	x, err := rand.Int(rand.Reader, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x, multiply(t, curve, P, x)
+}
+
+func TestBSGS(t *testing.T) {
+	curve := newCurve(t, 3, 5, 1000003)
+	N := big.NewInt(1001205) // 3²·5·19·1171
+	for i := 0; i < 8; i++ {
+		P := curve.RandomPoint()
+		n, err := curve.Order(P, N)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, Q := randomMultiple(t, curve, P, n)
+		got, err := curve.BSGS(P, Q, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Cmp(x) != 0 {
+			t.Errorf("BSGS(%s, [%s]P, %s) = %s", P, x, n, got)
+		}
+		// any multiple of the order will do
+		if got, err := curve.BSGS(P, Q, N); err != nil || !multiply(t, curve, P, got).Equals(Q) {
+			t.Errorf("BSGS(%s, [%s]P, N) = %s, %v", P, x, got, err)
+		}
+	}
+
+	P := curve.RandomPoint()
+	if _, err := curve.BSGS(P, P, big.NewInt(1000)); err == nil {
+		t.Error("BSGS accepted n not killing P")
+	}
+}
+
+func TestPollardRho(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	curve := newCurve(t, 3, 7, 1000003)
+	n := big.NewInt(999853) // prime number of points
+	for _, workers := range []int{1, 4} {
+		P := curve.RandomPoint()
+		x, Q := randomMultiple(t, curve, P, n)
+		got, err := curve.PollardRho(ctx, P, Q, n, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Cmp(x) != 0 {
+			t.Errorf("PollardRho(%s, [%s]P, %s, %d) = %s", P, x, n, workers, got)
+		}
+	}
+
+	P := curve.RandomPoint()
+	if got, err := curve.PollardRho(ctx, P, nil, n, 1); err != nil || got.Sign() != 0 {
+		t.Errorf("PollardRho(P, O) = %s, %v, want 0", got, err)
+	}
+}
+
+// TestPollardRhoNotInSubgroup Q of order 3 not in <P> for P of order 3, in E(F_73) ≅ Z/9 × Z/9
+func TestPollardRhoNotInSubgroup(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	curve := newCurve(t, 0, 2, 73)
+	N := big.NewInt(81)
+	s, err := curve.GroupStructure(N)
+	if err != nil {
+		t.Fatal(err)
+	}
+	three := big.NewInt(3)
+	if new(big.Int).Mod(s.N1, three).Sign() != 0 {
+		t.Fatalf("y² = x³ + 2 mod 73: %s, want 3 | N1", s)
+	}
+	P := multiply(t, curve, s.P2, new(big.Int).Quo(s.N2, three)) // of order 3
+	Q := multiply(t, curve, s.P1, new(big.Int).Quo(s.N1, three)) // of order 3, independent of P
+
+	// [3]P2 != O: rejected at once
+	if _, err := curve.PollardRho(ctx, P, s.P2, three, 1); !errors.Is(err, ec.ErrNotInSubgroup) {
+		t.Errorf("PollardRho(P, P2) = %v, want ErrNotInSubgroup", err)
+	}
+	// [3]Q = O but Q is not in <P>: the search must end without ctx
+	if _, err := curve.PollardRho(ctx, P, Q, three, 1); err == nil || ctx.Err() != nil {
+		t.Errorf("PollardRho(P, P1) = %v, want an error before the deadline", err)
+	}
+	if _, err := curve.DiscreteLog(ctx, P, Q, N); !errors.Is(err, ec.ErrNotInSubgroup) {
+		t.Errorf("DiscreteLog(P, P1) = %v, want ErrNotInSubgroup", err)
+	}
+	if _, err := curve.BSGS(P, Q, three); !errors.Is(err, ec.ErrNotInSubgroup) {
+		t.Errorf("BSGS(P, P1) = %v, want ErrNotInSubgroup", err)
+	}
+}
+
+func TestKangaroo(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	curve := newCurve(t, 3, 7, 1000003) // 999853 points, prime
+	cases := []struct {
+		a, b, x int64
+	}{
+		{0, 100, 57},             // small interval, enumerated
+		{400000, 500000, 400000}, // lower bound
+		{400000, 500000, 500000}, // upper bound
+		{400000, 500000, 431415},
+		{0, 300000, 271828},
+	}
+	for _, c := range cases {
+		P := curve.RandomPoint()
+		Q := multiply(t, curve, P, big.NewInt(c.x))
+		got, err := curve.Kangaroo(ctx, P, Q, big.NewInt(c.a), big.NewInt(c.b))
+		if err != nil {
+			t.Fatalf("Kangaroo(P, [%d]P, %d, %d): %v", c.x, c.a, c.b, err)
+		}
+		if got.Int64() != c.x {
+			t.Errorf("Kangaroo(P, [%d]P, %d, %d) = %s", c.x, c.a, c.b, got)
+		}
+	}
+
+	P := curve.RandomPoint()
+	if _, err := curve.Kangaroo(ctx, P, P, big.NewInt(10), big.NewInt(5)); err == nil {
+		t.Error("Kangaroo accepted an empty interval")
+	}
+	// the logarithm 7 is not in [100, 200]
+	if _, err := curve.Kangaroo(ctx, P, multiply(t, curve, P, big.NewInt(7)), big.NewInt(100), big.NewInt(200)); err == nil {
+		t.Error("Kangaroo found a logarithm outside of the interval")
+	}
+}
+
+func TestDiscreteLog(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	curve := newCurve(t, 3, 5, 1000003)
+	N := big.NewInt(1001205)
+	for i := 0; i < 8; i++ {
+		P := curve.RandomPoint()
+		n, err := curve.Order(P, N)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, Q := randomMultiple(t, curve, P, n)
+		got, err := curve.DiscreteLog(ctx, P, Q, N)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Cmp(x) != 0 {
+			t.Errorf("DiscreteLog(%s, [%s]P, N) = %s, order %s", P, x, got, n)
+		}
+	}
+
+	// prime order: solved by PollardRho above 2^40 only, by BSGS here
+	prime := newCurve(t, 3, 7, 1000003)
+	P := prime.RandomPoint()
+	x, Q := randomMultiple(t, prime, P, big.NewInt(999853))
+	if got, err := prime.DiscreteLog(ctx, P, Q, big.NewInt(999853)); err != nil || got.Cmp(x) != 0 {
+		t.Errorf("DiscreteLog(%s, [%s]P, 999853) = %s, %v", P, x, got, err)
+	}
+}
+
+func TestPohligHellman(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	curve := newCurve(t, 3, 5, 10007)
+	N, err := ec.CountPointsNaive(curve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	P := curve.RandomPoint()
+	n, err := curve.Order(P, N)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, Q := randomMultiple(t, curve, P, n)
+	got, err := curve.PohligHellman(ctx, P, Q, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Cmp(x) != 0 {
+		t.Errorf("PohligHellman(%s, [%s]P) = %s", P, x, got)
+	}
+
+	// a counter giving a wrong number of points
+	wrong := func(*ec.EllipticCurve) (*big.Int, error) { return new(big.Int).Add(N, big.NewInt(1)), nil }
+	if _, err := curve.PohligHellman(ctx, P, Q, wrong); err == nil {
+		t.Error("PohligHellman accepted a wrong number of points")
+	}
+}
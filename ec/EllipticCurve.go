@@ -272,16 +272,6 @@ func (ec *EllipticCurve) MultiplyPointByScalar(p *Point, n *big.Int) (*Point, er
 	return res, nil
 }
 
-// CheckHasseTheorem True iff the elliptic curve passes the hasse theorem stating that the number of points of the curve
-//
-//	within its finite field is close to the number of elements of the finite field itself
-//
-// i.e. |N - (q + 1)| <= 2 * sqrt(p), see https://en.wikipedia.org/wiki/Hasse%27s_theorem_on_elliptic_curves
-func (ec *EllipticCurve) CheckHasseTheorem() bool {
-	//TODO: use schoof to get the number of points of the elliptic curve
-	return ec.IsNonSingular() && true
-}
-
 func CreateEC() *EllipticCurve {
 	a, ok := new(big.Int).SetString("0x0000000000000000000000000000000000000000000000000000000000000000", 0)
 	if !ok {
@@ -0,0 +1,432 @@
+package ec
+
+import (
+	"fmt"
+	"goschoof/polynom"
+	"math/big"
+)
+
+// ExtensionField The finite field GF(p^k) = F_p[z]/(m(z)), m being the irreducible polynom of degree k given by
+// polynom.LowestIrreducible (k = 1 gives F_p itself). Its elements are polynoms in z of degree < k, always reduced.
+type ExtensionField struct {
+	p   *big.Int
+	k   int
+	q   *big.Int // p^k
+	mod *polynom.Modulus
+}
+
+// NewExtensionField Instantiate GF(p^k), p being prime and k >= 1.
+// Fails if no irreducible polynom of degree k was found (see polynom.LowestIrreducible).
+func NewExtensionField(p *big.Int, k int) (*ExtensionField, error) {
+	if k < 1 {
+		panic("NewExtensionField: degree must be >= 1")
+	}
+	m, err := polynom.LowestIrreducible(k, p)
+	if err != nil {
+		return nil, err
+	}
+	return &ExtensionField{
+		p:   new(big.Int).Set(p),
+		k:   k,
+		q:   new(big.Int).Exp(p, big.NewInt(int64(k)), nil),
+		mod: polynom.NewModulus(m),
+	}, nil
+}
+
+// GetP returns the characteristic p.
+func (F *ExtensionField) GetP() *big.Int {
+	return F.p
+}
+
+// Degree returns k, the degree of the extension.
+func (F *ExtensionField) Degree() int {
+	return F.k
+}
+
+// Order returns q = p^k, the number of elements of the field.
+func (F *ExtensionField) Order() *big.Int {
+	return F.q
+}
+
+// Modulus returns the irreducible polynom m defining the field.
+func (F *ExtensionField) Modulus() *polynom.Polynom {
+	return F.mod.Polynom()
+}
+
+// FromInt returns the element a of the prime field F_p.
+func (F *ExtensionField) FromInt(a *big.Int) *polynom.Polynom {
+	return polynom.NewPolynom([]*big.Int{a}, F.p)
+}
+
+// Zero returns 0.
+func (F *ExtensionField) Zero() *polynom.Polynom {
+	return F.FromInt(big.NewInt(0))
+}
+
+// One returns 1.
+func (F *ExtensionField) One() *polynom.Polynom {
+	return F.FromInt(big.NewInt(1))
+}
+
+// Add returns a + b.
+func (F *ExtensionField) Add(a, b *polynom.Polynom) *polynom.Polynom {
+	return a.Add(b)
+}
+
+// Sub returns a - b.
+func (F *ExtensionField) Sub(a, b *polynom.Polynom) *polynom.Polynom {
+	return a.Sub(b)
+}
+
+// Neg returns -a.
+func (F *ExtensionField) Neg(a *polynom.Polynom) *polynom.Polynom {
+	return F.Zero().Sub(a)
+}
+
+// Mul returns a·b.
+func (F *ExtensionField) Mul(a, b *polynom.Polynom) *polynom.Polynom {
+	return F.mod.MulMod(a, b)
+}
+
+// Exp returns a^e (e >= 0).
+func (F *ExtensionField) Exp(a *polynom.Polynom, e *big.Int) *polynom.Polynom {
+	return F.mod.PowMod(a, e)
+}
+
+// Inv returns 1/a, failing for a = 0.
+func (F *ExtensionField) Inv(a *polynom.Polynom) (*polynom.Polynom, error) {
+	if a.IsZero() {
+		return nil, fmt.Errorf("0 has no inverse in GF(%s^%d).\n", F.p, F.k)
+	}
+	inv, ok := polynom.PolyInvMod(a, F.mod.Polynom())
+	if !ok {
+		return nil, fmt.Errorf("%s has no inverse in GF(%s^%d).\n", a, F.p, F.k)
+	}
+	return F.mod.Reduce(inv), nil
+}
+
+// Div returns a/b, failing for b = 0.
+func (F *ExtensionField) Div(a, b *polynom.Polynom) (*polynom.Polynom, error) {
+	inv, err := F.Inv(b)
+	if err != nil {
+		return nil, err
+	}
+	return F.Mul(a, inv), nil
+}
+
+// Equals True iff a = b.
+func (F *ExtensionField) Equals(a, b *polynom.Polynom) bool {
+	return a.Equals(b)
+}
+
+// IsOne True iff a = 1.
+func (F *ExtensionField) IsOne(a *polynom.Polynom) bool {
+	return a.Equals(F.One())
+}
+
+// Random returns a uniformly chosen element of the field.
+func (F *ExtensionField) Random() *polynom.Polynom {
+	return polynom.RandomPolynom(F.k-1, F.p)
+}
+
+// Sqrt Returns a square root of a, ok being false when a is not a square. Tonelli-Shanks in GF(q), q - 1 = 2^s·t:
+// the square root of the part of a in the subgroup of order 2^s is corrected step by step with the powers of z^t,
+// z being a non-square. In characteristic 2 every element is a square, sqrt(a) = a^(q/2).
+func (F *ExtensionField) Sqrt(a *polynom.Polynom) (*polynom.Polynom, bool) {
+	if a.IsZero() {
+		return F.Zero(), true
+	}
+	if F.p.Cmp(big.NewInt(2)) == 0 {
+		return F.Exp(a, new(big.Int).Rsh(F.q, 1)), true
+	}
+	qMinus1 := new(big.Int).Sub(F.q, big.NewInt(1))
+	half := new(big.Int).Rsh(qMinus1, 1)
+	if !F.IsOne(F.Exp(a, half)) {
+		return nil, false
+	}
+
+	s := 0
+	t := new(big.Int).Set(qMinus1)
+	for t.Bit(0) == 0 {
+		t.Rsh(t, 1)
+		s++
+	}
+	z := F.Random()
+	for z.IsZero() || F.IsOne(F.Exp(z, half)) {
+		z = F.Random()
+	}
+
+	c := F.Exp(z, t)
+	x := F.Exp(a, new(big.Int).Rsh(new(big.Int).Add(t, big.NewInt(1)), 1)) // a^((t+1)/2)
+	b := F.Exp(a, t)
+	m := s
+	for !F.IsOne(b) {
+		// least i such as b^(2^i) = 1
+		i := 0
+		for b2 := b; !F.IsOne(b2); b2 = F.Mul(b2, b2) {
+			i++
+		}
+		g := c
+		for j := 0; j < m-i-1; j++ {
+			g = F.Mul(g, g)
+		}
+		x = F.Mul(x, g)
+		c = F.Mul(g, g)
+		b = F.Mul(b, c)
+		m = i
+	}
+	return x, true
+}
+
+// ExtPoint An affine point (X, Y) of an elliptic curve over an extension field, nil being the omega (neutral element).
+type ExtPoint struct {
+	X *polynom.Polynom
+	Y *polynom.Polynom
+}
+
+// Equals True iff both points have the same coordinates (nil, the omega, only equals itself).
+func (P *ExtPoint) Equals(Q *ExtPoint) bool {
+	if P == nil || Q == nil {
+		return P == nil && Q == nil
+	}
+	return P.X.Equals(Q.X) && P.Y.Equals(Q.Y)
+}
+
+// String Formats the point as (x, y), the neutral element (nil) being O.
+func (P *ExtPoint) String() string {
+	if P == nil {
+		return "O"
+	}
+	return fmt.Sprintf("(%s, %s)", P.X, P.Y)
+}
+
+// ExtendedCurve The elliptic curve y² = x³ + ax + b of F_p seen over GF(p^k), where the points of order n
+// missing over F_p appear (all of E[n] as soon as k is a multiple of the embedding degree, see the pairings).
+type ExtendedCurve struct {
+	curve *EllipticCurve
+	field *ExtensionField
+	a     *polynom.Polynom
+	b     *polynom.Polynom
+}
+
+// OverExtension Returns the curve over GF(p^k), see NewExtensionField.
+func (ec *EllipticCurve) OverExtension(k int) (*ExtendedCurve, error) {
+	F, err := NewExtensionField(ec.p, k)
+	if err != nil {
+		return nil, err
+	}
+	return &ExtendedCurve{curve: ec, field: F, a: F.FromInt(ec.a), b: F.FromInt(ec.b)}, nil
+}
+
+// Curve returns the curve over F_p.
+func (E *ExtendedCurve) Curve() *EllipticCurve {
+	return E.curve
+}
+
+// Field returns GF(p^k).
+func (E *ExtendedCurve) Field() *ExtensionField {
+	return E.field
+}
+
+// Lift Returns the point P of E(F_p) as a point of E(GF(p^k)).
+func (E *ExtendedCurve) Lift(P *Point) *ExtPoint {
+	if P == nil {
+		return nil
+	}
+	return &ExtPoint{X: E.field.FromInt(P.x), Y: E.field.FromInt(P.y)}
+}
+
+// rhs Returns x³ + ax + b
+func (E *ExtendedCurve) rhs(x *polynom.Polynom) *polynom.Polynom {
+	F := E.field
+	return F.Add(F.Mul(F.Add(F.Mul(x, x), E.a), x), E.b)
+}
+
+// IsOnCurve True iff y² = x³ + ax + b (the omega, nil, being on the curve).
+func (E *ExtendedCurve) IsOnCurve(P *ExtPoint) bool {
+	if P == nil {
+		return true
+	}
+	return E.field.Mul(P.Y, P.Y).Equals(E.rhs(P.X))
+}
+
+// extensionNaiveMaxQ the largest q = p^k for which the points over GF(q) are counted naively
+const extensionNaiveMaxQ = 1 << 20
+
+// CountPointsNaive Counts the points of the curve over GF(q), q = p^k, by going through all the x of GF(q): each x gives
+// 1 + χ(x³ + ax + b) points, χ(v) = v^((q-1)/2) being the quadratic character. Only for odd p and q <= 2^20.
+func (E *ExtendedCurve) CountPointsNaive() (*big.Int, error) {
+	F := E.field
+	if F.q.Cmp(big.NewInt(extensionNaiveMaxQ)) > 0 {
+		return nil, fmt.Errorf("q = %s too big to count the points naively.\n", F.q)
+	}
+	if F.p.Bit(0) == 0 {
+		return nil, fmt.Errorf("The points cannot be counted in characteristic 2.\n")
+	}
+	half := new(big.Int).Rsh(F.q, 1) // (q-1)/2
+	q := F.q.Int64()
+	N := big.NewInt(1) // the omega
+	for i := int64(0); i < q; i++ {
+		// x = sum(d_j z^j), d the digits of i in base p
+		coeffs := make([]*big.Int, F.k)
+		rest := big.NewInt(i)
+		for j := range coeffs {
+			coeffs[j] = new(big.Int)
+			rest.QuoRem(rest, F.p, coeffs[j])
+		}
+		v := E.rhs(polynom.NewPolynom(coeffs, F.p))
+		switch {
+		case v.IsZero():
+			N.Add(N, big.NewInt(1))
+		case F.IsOne(F.Exp(v, half)):
+			N.Add(N, big.NewInt(2))
+		}
+	}
+	return N, nil
+}
+
+// Neg Returns -P = (x, -y).
+func (E *ExtendedCurve) Neg(P *ExtPoint) *ExtPoint {
+	if P == nil {
+		return nil
+	}
+	return &ExtPoint{X: P.X.Copy(), Y: E.field.Neg(P.Y)}
+}
+
+// slope Returns the slope of the line through P and Q (the tangent when P = Q), nil when it is vertical
+func (E *ExtendedCurve) slope(P, Q *ExtPoint) *polynom.Polynom {
+	F := E.field
+	var num, den *polynom.Polynom
+	if P.X.Equals(Q.X) {
+		if !P.Y.Equals(Q.Y) || P.Y.IsZero() {
+			return nil // Q = -P
+		}
+		// (3x² + a) / 2y
+		num = F.Add(F.Mul(F.FromInt(big.NewInt(3)), F.Mul(P.X, P.X)), E.a)
+		den = F.Add(P.Y, P.Y)
+	} else {
+		num = F.Sub(Q.Y, P.Y)
+		den = F.Sub(Q.X, P.X)
+	}
+	lambda, err := F.Div(num, den)
+	if err != nil {
+		panic(err) // den != 0 in a field
+	}
+	return lambda
+}
+
+// Add Returns P + Q.
+func (E *ExtendedCurve) Add(P, Q *ExtPoint) *ExtPoint {
+	if P == nil {
+		return Q
+	}
+	if Q == nil {
+		return P
+	}
+	lambda := E.slope(P, Q)
+	if lambda == nil {
+		return nil
+	}
+	return E.third(P, Q, lambda)
+}
+
+// third Returns P + Q, lambda being the slope of the line through P and Q:
+// x = λ² - x_P - x_Q, y = λ(x_P - x) - y_P
+func (E *ExtendedCurve) third(P, Q *ExtPoint, lambda *polynom.Polynom) *ExtPoint {
+	F := E.field
+	x := F.Sub(F.Sub(F.Mul(lambda, lambda), P.X), Q.X)
+	y := F.Sub(F.Mul(lambda, F.Sub(P.X, x)), P.Y)
+	return &ExtPoint{X: x, Y: y}
+}
+
+// Multiply Returns [n]P (n >= 0), by double and add.
+func (E *ExtendedCurve) Multiply(P *ExtPoint, n *big.Int) *ExtPoint {
+	var res *ExtPoint
+	for i := n.BitLen() - 1; i >= 0; i-- {
+		res = E.Add(res, res)
+		if n.Bit(i) == 1 {
+			res = E.Add(res, P)
+		}
+	}
+	return res
+}
+
+// RandomPoint Returns a uniformly chosen affine point of the curve over GF(p^k), or nil (the omega)
+// if none was found after a few hundred draws.
+func (E *ExtendedCurve) RandomPoint() *ExtPoint {
+	F := E.field
+	for tries := 0; tries < 512; tries++ {
+		x := F.Random()
+		y, ok := F.Sqrt(E.rhs(x))
+		if !ok {
+			continue
+		}
+		if F.Random().Coeff(0).Bit(0) == 1 {
+			y = F.Neg(y)
+		}
+		return &ExtPoint{X: x, Y: y}
+	}
+	return nil
+}
+
+// RandomPointOfOrder Returns a random point of prime order n of the curve over GF(p^k), Nk being its number of points
+// over GF(p^k) (e.g. schoof.ZetaFromCount(curve, N).Count(k)): the n-part [Nk/n^v]R of a random point R (n^v || Nk)
+// is multiplied by n until the next multiple is O. Fails if n does not divide Nk.
+// The points of the largest cyclic factor are favoured, often those of E(F_p): see RandomTraceZeroPointOfOrder.
+func (E *ExtendedCurve) RandomPointOfOrder(n, Nk *big.Int) (*ExtPoint, error) {
+	return E.randomPointOfOrder(n, Nk, E.RandomPoint)
+}
+
+// RandomTraceZeroPointOfOrder Same as RandomPointOfOrder, the point being drawn from the points of trace O
+// (see TraceZero): for k > 1 the embedding degree of n, it is independent of E(F_p)[n], e.g. the second argument
+// of a non-degenerate Weil or Tate pairing with a rational point of order n.
+func (E *ExtendedCurve) RandomTraceZeroPointOfOrder(n, Nk *big.Int) (*ExtPoint, error) {
+	return E.randomPointOfOrder(n, Nk, func() *ExtPoint {
+		return E.TraceZero(E.RandomPoint())
+	})
+}
+
+// randomPointOfOrder see RandomPointOfOrder, the points being drawn by draw
+func (E *ExtendedCurve) randomPointOfOrder(n, Nk *big.Int, draw func() *ExtPoint) (*ExtPoint, error) {
+	if n.Cmp(big.NewInt(2)) < 0 || new(big.Int).Mod(Nk, n).Sign() != 0 {
+		return nil, fmt.Errorf("%s does not divide the number of points %s.\n", n, Nk)
+	}
+	v := valuation(Nk, n)
+	cofactor := new(big.Int).Quo(Nk, new(big.Int).Exp(n, big.NewInt(int64(v)), nil))
+	for draws := 0; draws < 4*pointDraws; draws++ {
+		R := E.Multiply(draw(), cofactor)
+		if R == nil {
+			continue
+		}
+		for i := 0; i < v; i++ {
+			next := E.Multiply(R, n)
+			if next == nil {
+				return R, nil
+			}
+			R = next
+		}
+		return nil, fmt.Errorf("%s is not the number of points over GF(%s^%d): [Nk]R != O.\n", Nk, E.field.p, E.field.k)
+	}
+	return nil, fmt.Errorf("No point of order %s found.\n", n)
+}
+
+// Frobenius Returns π(P) = (x^p, y^p), which fixes exactly the points of E(F_p).
+func (E *ExtendedCurve) Frobenius(P *ExtPoint) *ExtPoint {
+	if P == nil {
+		return nil
+	}
+	return &ExtPoint{X: E.field.Exp(P.X, E.field.p), Y: E.field.Exp(P.Y, E.field.p)}
+}
+
+// TraceZero Returns [k]P - Tr(P), Tr(P) = P + π(P) + ... + π^(k-1)(P) being the trace of P, a point of E(F_p).
+// The result has trace O, so π acts on it as [p]: for n prime, k > 1 the embedding degree and P of order n,
+// it is O or a point of order n independent of E(F_p)[n].
+func (E *ExtendedCurve) TraceZero(P *ExtPoint) *ExtPoint {
+	trace := P
+	conjugate := P
+	for i := 1; i < E.field.k; i++ {
+		conjugate = E.Frobenius(conjugate)
+		trace = E.Add(trace, conjugate)
+	}
+	return E.Add(E.Multiply(P, big.NewInt(int64(E.field.k))), E.Neg(trace))
+}
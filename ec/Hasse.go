@@ -0,0 +1,83 @@
+package ec
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// PointCounter Returns the number of points N of the curve, the omega included
+// (e.g. schoof.CountPoints, or CountPointsNaive for small p).
+type PointCounter func(curve *EllipticCurve) (*big.Int, error)
+
+// HasseResult Details of the check of Hasse's bound |N - (p + 1)| <= 2*sqrt(p) for an order N.
+type HasseResult struct {
+	N     *big.Int
+	Trace *big.Int // t = p + 1 - N
+	Bound *big.Int // floor(2*sqrt(p)): as t is an integer, |t| <= 2*sqrt(p) iff |t| <= floor(2*sqrt(p))
+	Holds bool     // |t| <= 2*sqrt(p)
+}
+
+func (res *HasseResult) String() string {
+	return fmt.Sprintf("N = %s, t = %s, |t| <= %s: %t", res.N, res.Trace, res.Bound, res.Holds)
+}
+
+// CheckHasseTheorem Checks that the number of points of the elliptic curve, computed by counter,
+// is close to the number of elements of its finite field, as stated by Hasse's theorem:
+// |N - (p + 1)| <= 2 * sqrt(p), see https://en.wikipedia.org/wiki/Hasse%27s_theorem_on_elliptic_curves
+// A nil counter means CountPointsNaive. The theorem only holds for non-singular curves: singular ones are an error.
+func (ec *EllipticCurve) CheckHasseTheorem(counter PointCounter) (*HasseResult, error) {
+	if !ec.IsNonSingular() {
+		return nil, fmt.Errorf("Elliptic curve is singular, Hasse theorem does not apply.\n")
+	}
+	if counter == nil {
+		counter = CountPointsNaive
+	}
+	N, err := counter(ec)
+	if err != nil {
+		return nil, err
+	}
+	return ec.CheckHasseBound(N), nil
+}
+
+// CheckHasseBound Checks Hasse's bound for the given order N, with exact integer arithmetic:
+// |t| <= 2*sqrt(p) <=> t² <= 4p, with t = p + 1 - N.
+func (ec *EllipticCurve) CheckHasseBound(N *big.Int) *HasseResult {
+	t := new(big.Int).Add(ec.p, big.NewInt(1))
+	t.Sub(t, N)
+
+	fourP := new(big.Int).Lsh(ec.p, 2)
+	tSquared := new(big.Int).Mul(t, t)
+	return &HasseResult{
+		N:     new(big.Int).Set(N),
+		Trace: t,
+		Bound: new(big.Int).Sqrt(fourP), // floor(sqrt(4p)) = floor(2*sqrt(p))
+		Holds: tSquared.Cmp(fourP) <= 0,
+	}
+}
+
+// CountPointsNaive Counts the points of the curve by going through all the x of F_p: each x gives
+// 1 + (x³ + ax + b | p) points, (.|p) being the Legendre symbol. Only suited to small p (O(p) operations).
+func CountPointsNaive(ec *EllipticCurve) (*big.Int, error) {
+	if !ec.p.IsInt64() || ec.p.Int64() > 1<<24 {
+		return nil, fmt.Errorf("p %s too big to count the points naively.\n", ec.p)
+	}
+
+	N := big.NewInt(1) // the omega
+	rhs := new(big.Int)
+	for x := big.NewInt(0); x.Cmp(ec.p) < 0; x.Add(x, big.NewInt(1)) {
+		rhs.Exp(x, big.NewInt(3), ec.p)         // x³
+		rhs.Add(rhs, new(big.Int).Mul(ec.a, x)) // x³ + ax
+		rhs.Add(rhs, ec.b)                      // x³ + ax + b
+		rhs.Mod(rhs, ec.p)
+		if rhs.Sign() == 0 {
+			N.Add(N, big.NewInt(1))
+			continue
+		}
+		if ec.p.Cmp(big.NewInt(2)) == 0 {
+			N.Add(N, big.NewInt(1)) // y = -y = 1
+		} else if big.Jacobi(rhs, ec.p) == 1 {
+			N.Add(N, big.NewInt(2))
+		}
+	}
+	return N, nil
+}
@@ -0,0 +1,133 @@
+package ec_test
+
+import (
+	"errors"
+	"goschoof/ec"
+	"goschoof/schoof"
+	"math/big"
+	"testing"
+)
+
+func newCurve(t *testing.T, a, b, p int64) *ec.EllipticCurve {
+	t.Helper()
+	curve, err := ec.NewEllipticCurve(big.NewInt(a), big.NewInt(b), big.NewInt(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return curve
+}
+
+// countByEnumeration Counts the points of the curve by testing all the (x, y), O(p²)
+func countByEnumeration(t *testing.T, curve *ec.EllipticCurve) *big.Int {
+	t.Helper()
+	p := curve.GetP().Int64()
+	N := int64(1) // the omega
+	for x := int64(0); x < p; x++ {
+		for y := int64(0); y < p; y++ {
+			P, err := ec.NewPoint(big.NewInt(x), big.NewInt(y))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if curve.PointIsOnCurve(P) {
+				N++
+			}
+		}
+	}
+	return big.NewInt(N)
+}
+
+func TestCountPointsNaive(t *testing.T) {
+	cases := []struct {
+		a, b, p int64
+		N       int64 // 0: unknown, counted by enumeration
+	}{
+		{1, 1, 23, 28}, // the textbook example
+		{0, 7, 17, 18},
+		{2, 3, 97, 0},
+		{1, 0, 103, 104}, // supersingular, p = 3 mod 4
+		{0, 1, 101, 102}, // supersingular, p = 2 mod 3
+		{5, 11, 211, 0},
+		{7, 13, 1009, 0},
+	}
+	for _, c := range cases {
+		curve := newCurve(t, c.a, c.b, c.p)
+		want := big.NewInt(c.N)
+		if c.N == 0 {
+			want = countByEnumeration(t, curve)
+		}
+		got, err := ec.CountPointsNaive(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Cmp(want) != 0 {
+			t.Errorf("CountPointsNaive(y² = x³ + %dx + %d mod %d) = %s, want %s", c.a, c.b, c.p, got, want)
+		}
+
+		res, err := curve.CheckHasseTheorem(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Holds || res.N.Cmp(want) != 0 {
+			t.Errorf("CheckHasseTheorem(y² = x³ + %dx + %d mod %d) = %s", c.a, c.b, c.p, res)
+		}
+	}
+}
+
+func TestCheckHasseTheoremSchoof(t *testing.T) {
+	curve := newCurve(t, 3, 5, 1000003)
+	want, err := ec.CountPointsNaive(curve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := curve.CheckHasseTheorem(schoof.CountPoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Holds || res.N.Cmp(want) != 0 {
+		t.Errorf("CheckHasseTheorem(schoof.CountPoints) = %s, want N = %s", res, want)
+	}
+}
+
+func TestCheckHasseBound(t *testing.T) {
+	// p = 97: 2*sqrt(97) = 19.69..., the allowed traces are |t| <= 19
+	curve := newCurve(t, 2, 3, 97)
+	cases := []struct {
+		N     int64
+		holds bool
+	}{
+		{98, true},
+		{98 - 19, true},
+		{98 + 19, true},
+		{98 - 20, false},
+		{98 + 20, false},
+	}
+	for _, c := range cases {
+		res := curve.CheckHasseBound(big.NewInt(c.N))
+		if res.Holds != c.holds || res.Bound.Int64() != 19 || res.Trace.Int64() != 98-c.N {
+			t.Errorf("CheckHasseBound(%d) = %s, want holds = %t", c.N, res, c.holds)
+		}
+	}
+
+	// secp256k1 and its published group order
+	secp := ec.CreateEC()
+	n, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	if res := secp.CheckHasseBound(n); !res.Holds {
+		t.Errorf("secp256k1: %s", res)
+	}
+}
+
+func TestCheckHasseTheoremErrors(t *testing.T) {
+	if _, err := newCurve(t, 0, 0, 97).CheckHasseTheorem(nil); err == nil {
+		t.Error("no error for the singular y² = x³")
+	}
+	failing := errors.New("counter failure")
+	_, err := newCurve(t, 2, 3, 97).CheckHasseTheorem(func(*ec.EllipticCurve) (*big.Int, error) {
+		return nil, failing
+	})
+	if !errors.Is(err, failing) {
+		t.Errorf("the counter error was not returned: %v", err)
+	}
+	if _, err := ec.CountPointsNaive(ec.CreateEC()); err == nil {
+		t.Error("CountPointsNaive accepted a 256 bits p")
+	}
+}
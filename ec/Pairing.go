@@ -0,0 +1,167 @@
+package ec
+
+import (
+	"errors"
+	"fmt"
+	"goschoof/polynom"
+	"math/big"
+)
+
+// errDegenerate Returned by miller when a line or a vertical vanishes at the evaluation point
+var errDegenerate = errors.New("Miller function evaluated at one of its zeros or poles.\n")
+
+// miller Returns f_(n,P)(Q) with Miller's algorithm, f_(n,P) being the function of divisor n(P) - ([n]P) - (n-1)(O)
+// normalized at O, so that f_(n,P) has divisor n(P) - n(O) when [n]P = O. Built by double and add on n with
+// f_(i+j) = f_i·f_j·l_(T,R) / v_(T+R), l_(T,R) being the line through T = [i]P and R = [j]P (the tangent if T = R)
+// and v_S the vertical line through S. Fails with errDegenerate when one of these lines vanishes at Q.
+func (E *ExtendedCurve) miller(P, Q *ExtPoint, n *big.Int) (*polynom.Polynom, error) {
+	if Q == nil {
+		return nil, errDegenerate
+	}
+	F := E.field
+	f := F.One()
+	T := P
+	for i := n.BitLen() - 2; i >= 0; i-- {
+		g, S, err := E.lineRatio(T, T, Q)
+		if err != nil {
+			return nil, err
+		}
+		f = F.Mul(F.Mul(f, f), g)
+		T = S
+		if n.Bit(i) == 1 {
+			g, S, err = E.lineRatio(T, P, Q)
+			if err != nil {
+				return nil, err
+			}
+			f = F.Mul(f, g)
+			T = S
+		}
+	}
+	return f, nil
+}
+
+// lineRatio Returns l_(T,R)(Q) / v_(T+R)(Q) and T + R. The ratio is 1 if T or R is O (the line through O and S
+// being the vertical through S), and l_(T,R) is the vertical x - x_T when T + R = O (v_O = 1).
+func (E *ExtendedCurve) lineRatio(T, R, Q *ExtPoint) (*polynom.Polynom, *ExtPoint, error) {
+	F := E.field
+	if T == nil || R == nil {
+		return F.One(), E.Add(T, R), nil
+	}
+	lambda := E.slope(T, R)
+	if lambda == nil {
+		l := F.Sub(Q.X, T.X)
+		if l.IsZero() {
+			return nil, nil, errDegenerate
+		}
+		return l, nil, nil
+	}
+	S := E.third(T, R, lambda)
+	// l(Q) = y_Q - y_T - λ(x_Q - x_T), v(Q) = x_Q - x_S
+	l := F.Sub(F.Sub(Q.Y, T.Y), F.Mul(lambda, F.Sub(Q.X, T.X)))
+	v := F.Sub(Q.X, S.X)
+	if l.IsZero() || v.IsZero() {
+		return nil, nil, errDegenerate
+	}
+	g, err := F.Div(l, v)
+	if err != nil {
+		return nil, nil, err
+	}
+	return g, S, nil
+}
+
+// WeilPairing Returns the Weil pairing e_n(P, Q), an n-th root of unity of GF(p^k), P and Q being points of E[n]:
+// e_n(P, Q) = (-1)^n f_(n,P)(Q) / f_(n,Q)(P), see miller. It is bilinear, alternating (e_n(P, P) = 1) and
+// non-degenerate: e_n(P, Q) is a primitive n-th root of unity when n is prime and P, Q generate E[n].
+// The lines of Miller's algorithm only vanish at Q (resp. P) when P and Q are dependent, the pairing being then 1.
+// Fails if [n]P != O or [n]Q != O.
+func (E *ExtendedCurve) WeilPairing(P, Q *ExtPoint, n *big.Int) (*polynom.Polynom, error) {
+	if err := E.checkTorsion(P, n); err != nil {
+		return nil, err
+	}
+	if err := E.checkTorsion(Q, n); err != nil {
+		return nil, err
+	}
+	F := E.field
+	if P == nil || Q == nil || P.Equals(Q) {
+		return F.One(), nil
+	}
+
+	num, err := E.miller(P, Q, n)
+	if errors.Is(err, errDegenerate) {
+		return F.One(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	den, err := E.miller(Q, P, n)
+	if errors.Is(err, errDegenerate) {
+		return F.One(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	e, err := F.Div(num, den)
+	if err != nil {
+		return nil, err
+	}
+	if n.Bit(0) == 1 {
+		e = F.Neg(e)
+	}
+	return e, nil
+}
+
+// TatePairing Returns the reduced Tate pairing t_n(P, Q) = f_(n,P)(D_Q)^((q-1)/n), an n-th root of unity of GF(q),
+// q = p^k: P is a point of E[n] (usually of E(F_p)), Q any point of E(GF(q)), n dividing q - 1 (k being then
+// a multiple of the embedding degree). The final exponentiation makes the value independent of the divisor D_Q
+// chosen in the class of (Q) - (O): f_(n,P)(Q) is used when defined, else f_(n,P)(Q + R) / f_(n,P)(R) for a random R.
+// Bilinear, and non-degenerate: for n prime, t_n(P, Q) = 1 for all Q only when P = O.
+func (E *ExtendedCurve) TatePairing(P, Q *ExtPoint, n *big.Int) (*polynom.Polynom, error) {
+	if err := E.checkTorsion(P, n); err != nil {
+		return nil, err
+	}
+	if !E.IsOnCurve(Q) {
+		return nil, fmt.Errorf("Given point %s is not on the curve.\n", Q)
+	}
+	F := E.field
+	qMinus1 := new(big.Int).Sub(F.q, big.NewInt(1))
+	exponent, rem := new(big.Int).QuoRem(qMinus1, n, new(big.Int))
+	if rem.Sign() != 0 {
+		return nil, fmt.Errorf("%s does not divide p^k - 1 = %s, GF(%s^%d) has no primitive %s-th root of unity.\n",
+			n, qMinus1, F.p, F.k, n)
+	}
+	if P == nil || Q == nil {
+		return F.One(), nil
+	}
+
+	f, err := E.miller(P, Q, n)
+	for tries := 0; errors.Is(err, errDegenerate) && tries < 64; tries++ {
+		// D_Q = (Q + R) - (R)
+		R := E.RandomPoint()
+		var num, den *polynom.Polynom
+		if num, err = E.miller(P, E.Add(Q, R), n); err != nil {
+			continue
+		}
+		if den, err = E.miller(P, R, n); err != nil {
+			continue
+		}
+		f, err = F.Div(num, den)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return F.Exp(f, exponent), nil
+}
+
+// checkTorsion Returns an error unless P is a point of the curve killed by n
+func (E *ExtendedCurve) checkTorsion(P *ExtPoint, n *big.Int) error {
+	if n.Sign() <= 0 {
+		return fmt.Errorf("Order %s must be positive.\n", n)
+	}
+	if !E.IsOnCurve(P) {
+		return fmt.Errorf("Given point %s is not on the curve.\n", P)
+	}
+	if E.Multiply(P, n) != nil {
+		return fmt.Errorf("[%s]%s != O, the point is not of order dividing %s.\n", n, P, n)
+	}
+	return nil
+}
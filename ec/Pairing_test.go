@@ -0,0 +1,125 @@
+package ec_test
+
+import (
+	"goschoof/ec"
+	"goschoof/polynom"
+	"math/big"
+	"testing"
+)
+
+// pairingSetup Returns the supersingular y² = x³ + x mod p (p = 3 mod 4, p + 1 points) over GF(p²), a rational
+// point P of prime order n | p + 1 and a point Q of order n of trace O, independent of P (embedding degree 2)
+func pairingSetup(t *testing.T, p, n int64) (*ec.ExtendedCurve, *ec.ExtPoint, *ec.ExtPoint, *big.Int) {
+	t.Helper()
+	curve := newCurve(t, 1, 0, p)
+	N := big.NewInt(p + 1)
+	order := big.NewInt(n)
+	E, err := curve.OverExtension(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	P, err := curve.RandomPointOfOrder(order, N)
+	if err != nil {
+		t.Fatal(err)
+	}
+	N2 := new(big.Int).Mul(N, N) // #E(GF(p²)) = (p + 1)²
+	Q, err := E.RandomTraceZeroPointOfOrder(order, N2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return E, E.Lift(P), Q, order
+}
+
+func pairingPow(E *ec.ExtendedCurve, e *polynom.Polynom, k int64) *polynom.Polynom {
+	return E.Field().Exp(e, big.NewInt(k))
+}
+
+func TestWeilPairing(t *testing.T) {
+	for _, c := range []struct{ p, n int64 }{{59, 5}, {43, 11}, {103, 13}} {
+		E, P, Q, n := pairingSetup(t, c.p, c.n)
+		F := E.Field()
+		weil := func(A, B *ec.ExtPoint) *polynom.Polynom {
+			t.Helper()
+			e, err := E.WeilPairing(A, B, n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return e
+		}
+
+		e := weil(P, Q)
+		// non-degenerate: a primitive n-th root of unity
+		if F.IsOne(e) || !F.IsOne(pairingPow(E, e, c.n)) {
+			t.Errorf("p = %d: e_%d(P, Q) = %s is not a primitive %d-th root of unity", c.p, c.n, e, c.n)
+		}
+		// alternating
+		if !F.IsOne(weil(P, P)) || !F.IsOne(weil(Q, Q)) {
+			t.Errorf("p = %d: e(P, P) or e(Q, Q) != 1", c.p)
+		}
+		if !F.IsOne(F.Mul(e, weil(Q, P))) {
+			t.Errorf("p = %d: e(Q, P) != e(P, Q)^-1", c.p)
+		}
+		// bilinear
+		P2 := E.Multiply(P, big.NewInt(2))
+		Q3 := E.Multiply(Q, big.NewInt(3))
+		if !F.Equals(weil(P2, Q), pairingPow(E, e, 2)) {
+			t.Errorf("p = %d: e(2P, Q) != e(P, Q)²", c.p)
+		}
+		if !F.Equals(weil(P, Q3), pairingPow(E, e, 3)) {
+			t.Errorf("p = %d: e(P, 3Q) != e(P, Q)³", c.p)
+		}
+		if !F.Equals(weil(P2, Q3), pairingPow(E, e, 6)) {
+			t.Errorf("p = %d: e(2P, 3Q) != e(P, Q)⁶", c.p)
+		}
+		if !F.Equals(weil(E.Add(P, Q), Q), e) {
+			t.Errorf("p = %d: e(P + Q, Q) != e(P, Q) e(Q, Q)", c.p)
+		}
+	}
+}
+
+func TestTatePairing(t *testing.T) {
+	for _, c := range []struct{ p, n int64 }{{59, 5}, {43, 11}, {103, 13}} {
+		E, P, Q, n := pairingSetup(t, c.p, c.n)
+		F := E.Field()
+		tate := func(A, B *ec.ExtPoint) *polynom.Polynom {
+			t.Helper()
+			e, err := E.TatePairing(A, B, n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return e
+		}
+
+		e := tate(P, Q)
+		if F.IsOne(e) || !F.IsOne(pairingPow(E, e, c.n)) {
+			t.Errorf("p = %d: t_%d(P, Q) = %s is not a primitive %d-th root of unity", c.p, c.n, e, c.n)
+		}
+		P2 := E.Multiply(P, big.NewInt(2))
+		Q3 := E.Multiply(Q, big.NewInt(3))
+		if !F.Equals(tate(P2, Q), pairingPow(E, e, 2)) {
+			t.Errorf("p = %d: t(2P, Q) != t(P, Q)²", c.p)
+		}
+		if !F.Equals(tate(P, Q3), pairingPow(E, e, 3)) {
+			t.Errorf("p = %d: t(P, 3Q) != t(P, Q)³", c.p)
+		}
+		if !F.IsOne(tate(P, nil)) || !F.IsOne(tate(nil, Q)) {
+			t.Errorf("p = %d: t(P, O) or t(O, Q) != 1", c.p)
+		}
+	}
+}
+
+func TestPairingErrors(t *testing.T) {
+	E, _, Q, n := pairingSetup(t, 59, 5)
+	// a point of order 2 (0, 0) is not killed by 5
+	T, err := ec.NewPoint(big.NewInt(0), big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := E.WeilPairing(E.Lift(T), Q, n); err == nil {
+		t.Error("WeilPairing accepted a point not of order dividing n")
+	}
+	// 7 does not divide 59² - 1
+	if _, err := E.TatePairing(nil, Q, big.NewInt(7)); err == nil {
+		t.Error("TatePairing accepted n not dividing p^k - 1")
+	}
+}
@@ -17,6 +17,7 @@ func NewPoint(x, y *big.Int) (*Point, error) {
 	return &Point{x, y}, nil
 }
 
+// Equals True iff both points have the same coordinates (nil, the omega, only equals itself).
 func (p *Point) Equals(q *Point) bool {
 	if p == nil && q == nil {
 		return true
@@ -26,7 +27,7 @@ func (p *Point) Equals(q *Point) bool {
 		return false
 	}
 
-	return p.x == q.x && p.y == q.y
+	return p.x.Cmp(q.x) == 0 && p.y.Cmp(q.y) == 0
 }
 
 // CopyPoint Creates a deep copy of the given point.
@@ -54,6 +55,14 @@ func (p *Point) Frobenius(mod *big.Int) (*big.Int, *big.Int) {
 	return x, y
 }
 
+// String Formats the point as (x, y), the neutral element (nil) being O.
+func (p *Point) String() string {
+	if p == nil {
+		return "O"
+	}
+	return fmt.Sprintf("(%s, %s)", p.x, p.y)
+}
+
 func (p *Point) GetX() *big.Int {
 	return p.x
 }
@@ -0,0 +1,115 @@
+package ec
+
+import (
+	"fmt"
+	"goschoof/utils"
+	"math/big"
+)
+
+// MaxEmbeddingDegree the largest embedding degree looked for by EmbeddingDegree: above it, pairings map the
+// discrete logarithm to fields far too big for the reduction to be of any use
+const MaxEmbeddingDegree = 100
+
+// MOVMaxDegree the largest embedding degree for which the MOV / Frey-Rück reduction is flagged as practical
+// (supersingular curves have k <= 6, k <= 2 for p > 3)
+const MOVMaxDegree = 6
+
+// SecurityReport Outcome of the security checks of a curve, from its number of points N.
+type SecurityReport struct {
+	N               *big.Int
+	Order           *big.Int // n, the largest prime factor of N: the order of the main subgroup
+	Cofactor        *big.Int // N / n
+	Trace           *big.Int // t = p + 1 - N
+	EmbeddingDegree int      // smallest k such as n | p^k - 1, 0 when above MaxEmbeddingDegree (or for n = p)
+	Supersingular   bool     // p | t
+	MOV             bool     // 1 <= k <= MOVMaxDegree: the discrete logarithm in <P> reduces to GF(p^k)* by a pairing
+}
+
+// Err Returns an error if the curve must be rejected for cryptographic use: supersingular, or with a practical
+// MOV / Frey-Rück reduction.
+func (r *SecurityReport) Err() error {
+	if r.Supersingular {
+		return fmt.Errorf("Supersingular curve (t = %s), the MOV reduction applies with embedding degree %d.\n", r.Trace, r.EmbeddingDegree)
+	}
+	if r.MOV {
+		return fmt.Errorf("Embedding degree %d, the discrete logarithm reduces to GF(p^%d) by the MOV / Frey-Rück attack.\n",
+			r.EmbeddingDegree, r.EmbeddingDegree)
+	}
+	return nil
+}
+
+func (r *SecurityReport) String() string {
+	k := "> " + fmt.Sprint(MaxEmbeddingDegree)
+	if r.EmbeddingDegree > 0 {
+		k = fmt.Sprint(r.EmbeddingDegree)
+	}
+	return fmt.Sprintf("N = %s = %s * %s, t = %s, embedding degree %s, supersingular: %t, MOV/FR reduction practical: %t",
+		r.N, r.Cofactor, r.Order, r.Trace, k, r.Supersingular, r.MOV)
+}
+
+// CheckSecurity Runs the security checks on the curve, its number of points being computed by counter
+// (e.g. schoof.CountPoints, a nil counter meaning CountPointsNaive), see CheckSecurityOrder.
+func (ec *EllipticCurve) CheckSecurity(counter PointCounter) (*SecurityReport, error) {
+	if counter == nil {
+		counter = CountPointsNaive
+	}
+	N, err := counter(ec)
+	if err != nil {
+		return nil, err
+	}
+	return ec.CheckSecurityOrder(N)
+}
+
+// CheckSecurityOrder Runs the security checks on the curve of N points: N is factored to find the order n of
+// the main subgroup, then its embedding degree k tells whether the MOV (Weil pairing) or Frey-Rück (Tate pairing)
+// reduction maps the discrete logarithm of order n to the multiplicative group of a small enough field GF(p^k).
+// Supersingular curves always are (k <= 6). Fails if N is out of the Hasse interval or cannot be factored.
+func (ec *EllipticCurve) CheckSecurityOrder(N *big.Int) (*SecurityReport, error) {
+	hasse := ec.CheckHasseBound(N)
+	if !hasse.Holds {
+		return nil, fmt.Errorf("%s is not a possible number of points: %s.\n", N, hasse)
+	}
+	factors, ok := utils.Factorize(N)
+	if !ok {
+		return nil, fmt.Errorf("Could not factor %s.\n", N)
+	}
+
+	n := big.NewInt(1)
+	for _, pp := range factors {
+		if pp.P.Cmp(n) > 0 {
+			n = pp.P
+		}
+	}
+	k, _ := ec.EmbeddingDegree(n, MaxEmbeddingDegree)
+	return &SecurityReport{
+		N:               new(big.Int).Set(N),
+		Order:           new(big.Int).Set(n),
+		Cofactor:        new(big.Int).Quo(N, n),
+		Trace:           hasse.Trace,
+		EmbeddingDegree: k,
+		Supersingular:   ec.IsSupersingularTrace(hasse.Trace),
+		MOV:             k >= 1 && k <= MOVMaxDegree,
+	}, nil
+}
+
+// EmbeddingDegree Returns the embedding degree of the subgroup of order n, the smallest k >= 1 such as n | p^k - 1
+// (the order of p mod n), i.e. the degree of the smallest extension GF(p^k) holding the n-th roots of unity that
+// the pairings take their values in. ok is false when k would exceed maxK, or does not exist (n and p not coprime).
+func (ec *EllipticCurve) EmbeddingDegree(n *big.Int, maxK int) (int, bool) {
+	if n.Cmp(big.NewInt(1)) <= 0 {
+		return 1, true
+	}
+	pk := new(big.Int).Mod(ec.p, n)
+	if pk.Sign() == 0 {
+		return 0, false
+	}
+	power := new(big.Int).Set(pk)
+	for k := 1; k <= maxK; k++ {
+		if power.Cmp(big.NewInt(1)) == 0 {
+			return k, true
+		}
+		power.Mul(power, pk)
+		power.Mod(power, n)
+	}
+	return 0, false
+}
@@ -0,0 +1,81 @@
+package ec_test
+
+import (
+	"goschoof/ec"
+	"math/big"
+	"testing"
+)
+
+func TestCheckSecuritySupersingular(t *testing.T) {
+	cases := []struct {
+		a, b, p int64
+	}{
+		{1, 0, 59},   // y² = x³ + x, p = 3 mod 4
+		{1, 0, 103},  // idem
+		{1, 0, 1019}, // idem, N = 1020 = 4·3·5·17
+		{0, 1, 101},  // y² = x³ + 1, p = 2 mod 3
+	}
+	for _, c := range cases {
+		curve := newCurve(t, c.a, c.b, c.p)
+		report, err := curve.CheckSecurity(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.N.Int64() != c.p+1 || report.Trace.Sign() != 0 {
+			t.Errorf("y² = x³ + %dx + %d mod %d: N = %s, t = %s, want p + 1 points", c.a, c.b, c.p, report.N, report.Trace)
+		}
+		if !report.Supersingular || report.EmbeddingDegree != 2 || !report.MOV {
+			t.Errorf("y² = x³ + %dx + %d mod %d not flagged: %s", c.a, c.b, c.p, report)
+		}
+		if report.Err() == nil {
+			t.Errorf("y² = x³ + %dx + %d mod %d accepted", c.a, c.b, c.p)
+		}
+	}
+}
+
+func TestCheckSecuritySecp256k1(t *testing.T) {
+	curve := ec.CreateEC()
+	n, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	report, err := curve.CheckSecurityOrder(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Order.Cmp(n) != 0 || report.Cofactor.Int64() != 1 {
+		t.Errorf("secp256k1: order %s, cofactor %s", report.Order, report.Cofactor)
+	}
+	if report.Supersingular || report.MOV || report.EmbeddingDegree != 0 {
+		t.Errorf("secp256k1 flagged: %s", report)
+	}
+	if err := report.Err(); err != nil {
+		t.Errorf("secp256k1 rejected: %v", err)
+	}
+}
+
+func TestEmbeddingDegree(t *testing.T) {
+	curve := newCurve(t, 1, 0, 59)
+	cases := []struct {
+		n    int64
+		k    int
+		ok   bool
+		maxK int
+	}{
+		{29, 1, true, 10}, // 29 | 59 - 1
+		{5, 2, true, 10},  // 5 | 59 + 1
+		{3, 2, true, 10},
+		{7, 6, true, 10},   // 59 = 3 mod 7, which generates (Z/7Z)*
+		{7, 0, false, 2},   // above maxK
+		{59, 0, false, 10}, // n = p
+	}
+	for _, c := range cases {
+		k, ok := curve.EmbeddingDegree(big.NewInt(c.n), c.maxK)
+		if k != c.k || ok != c.ok {
+			t.Errorf("EmbeddingDegree(%d, %d) = %d, %t, want %d, %t", c.n, c.maxK, k, ok, c.k, c.ok)
+		}
+	}
+}
+
+func TestCheckSecurityOrderOutOfHasse(t *testing.T) {
+	if _, err := newCurve(t, 1, 0, 59).CheckSecurityOrder(big.NewInt(100)); err == nil {
+		t.Error("an order out of the Hasse interval was accepted")
+	}
+}
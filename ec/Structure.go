@@ -0,0 +1,317 @@
+package ec
+
+import (
+	"crypto/rand"
+	"fmt"
+	"goschoof/utils"
+	"math/big"
+)
+
+// structureAttempts how many points of maximal order are tried before giving up on the group structure
+const structureAttempts = 16
+
+// pointDraws how many random points are drawn for each step of the group structure search
+const pointDraws = 32
+
+// GroupStructure The abelian group E(F_p) ≅ Z/N1 × Z/N2, with N1 | N2 and N1 | p - 1 (N1 = 1 for a cyclic group).
+// Every point is [i]P1 + [j]P2 for a unique pair 0 <= i < N1, 0 <= j < N2.
+type GroupStructure struct {
+	N1 *big.Int
+	N2 *big.Int
+	P1 *Point // of order N1 (nil, the omega, when N1 = 1)
+	P2 *Point // of order N2
+}
+
+func (s *GroupStructure) String() string {
+	if s.N1.Cmp(big.NewInt(1)) == 0 {
+		return fmt.Sprintf("Z/%s, generated by %s", s.N2, s.P2)
+	}
+	return fmt.Sprintf("Z/%s × Z/%s, generated by %s and %s", s.N1, s.N2, s.P1, s.P2)
+}
+
+// RandomPoint Returns a uniformly chosen affine point of the curve, or nil (the omega) if none was found
+// after a few hundred draws (the curve having then hardly any point).
+func (ec *EllipticCurve) RandomPoint() *Point {
+	for tries := 0; tries < 512; tries++ {
+		x, err := rand.Int(rand.Reader, ec.p)
+		if err != nil {
+			panic(err)
+		}
+		y, ok := ec.ProcessYFrom(x)
+		if !ok {
+			continue
+		}
+		// either of the two y
+		sign, err := rand.Int(rand.Reader, big.NewInt(2))
+		if err != nil {
+			panic(err)
+		}
+		if sign.Sign() == 1 {
+			y.Sub(ec.p, y).Mod(y, ec.p)
+		}
+		return &Point{x, y}
+	}
+	return nil
+}
+
+// GroupStructure Returns the structure of the group of the points of the curve, N being its number of points
+// (e.g. from schoof.Schoof). N is factored, then:
+//   - a point P2 of maximal order N2 is built from random points, combining their orders prime by prime;
+//   - for each prime q of N1 = N / N2, a point of order q^a (q^a || N1) independent of P2 is built from a random point R:
+//     its q-part R_q is shifted by a multiple of P2 found by discrete logarithm, so that [q^a]R_q = O.
+//
+// The result is proven: the orders are exact, and <P1> ∩ <P2> = {O} is checked on each prime of N1.
+// Fails if N cannot be fully factored, if N is not the number of points, or (very unlikely) if the random search did not succeed.
+func (ec *EllipticCurve) GroupStructure(N *big.Int) (*GroupStructure, error) {
+	if !ec.IsNonSingular() {
+		return nil, fmt.Errorf("Elliptic curve is singular, its points do not form a group.\n")
+	}
+	if N.Sign() <= 0 {
+		return nil, fmt.Errorf("Invalid number of points %s.\n", N)
+	}
+	factors, ok := utils.Factorize(N)
+	if !ok {
+		return nil, fmt.Errorf("Could not factor the number of points %s.\n", N)
+	}
+	pMinus1 := new(big.Int).Sub(ec.p, big.NewInt(1))
+
+	for attempt := 0; attempt < structureAttempts; attempt++ {
+		P2, N2, err := ec.maximalOrderPoint(N, factors, pMinus1)
+		if err != nil {
+			return nil, err
+		}
+		N1 := new(big.Int).Quo(N, N2)
+		if new(big.Int).Mod(N2, N1).Sign() != 0 || new(big.Int).Mod(pMinus1, N1).Sign() != 0 {
+			continue
+		}
+
+		var P1 *Point
+		found := true
+		for _, pp := range factors {
+			a := valuation(N1, pp.P)
+			if a == 0 {
+				continue
+			}
+			S, ok := ec.independentPoint(N, pp, a, P2, N2)
+			if !ok {
+				found = false
+				break
+			}
+			P1 = ec.add(P1, S)
+		}
+		if found {
+			return &GroupStructure{N1: N1, N2: N2, P1: P1, P2: P2}, nil
+		}
+	}
+	return nil, fmt.Errorf("Could not find the group structure for N = %s.\n", N)
+}
+
+// Elements Returns all the points of the group, as [i]P1 + [j]P2 (only for groups of at most 2^20 points).
+func (s *GroupStructure) Elements(ec *EllipticCurve) ([]*Point, error) {
+	if new(big.Int).Mul(s.N1, s.N2).Cmp(big.NewInt(1<<20)) > 0 {
+		return nil, fmt.Errorf("Group of %s points too big to be enumerated.\n", new(big.Int).Mul(s.N1, s.N2))
+	}
+	var elements []*Point
+	var iP1 *Point
+	for i := int64(0); i < s.N1.Int64(); i++ {
+		current := iP1
+		for j := int64(0); j < s.N2.Int64(); j++ {
+			elements = append(elements, current)
+			current = ec.add(current, s.P2)
+		}
+		iP1 = ec.add(iP1, s.P1)
+	}
+	return elements, nil
+}
+
+// maximalOrderPoint Returns a point of order m, the largest found from random points, stopping as soon as
+// N/m divides both m and p - 1 (necessary for m to be the exponent of the group).
+// Fails if N does not kill a point, N being then not the number of points.
+func (ec *EllipticCurve) maximalOrderPoint(N *big.Int, factors []utils.PrimePower, pMinus1 *big.Int) (*Point, *big.Int, error) {
+	var P *Point
+	m := big.NewInt(1)
+	for draw := 0; draw < pointDraws; draw++ {
+		R := ec.RandomPoint()
+		if ec.mul(R, N) != nil {
+			return nil, nil, fmt.Errorf("%s is not the number of points: [N]%s != O.\n", N, R)
+		}
+		r := ec.orderOf(R, N, factors)
+		P, m = ec.combineOrders(P, m, R, r, factors)
+
+		n1 := new(big.Int).Quo(N, m)
+		if new(big.Int).Mod(m, n1).Sign() == 0 && new(big.Int).Mod(pMinus1, n1).Sign() == 0 {
+			break
+		}
+	}
+	return P, m, nil
+}
+
+// combineOrders Returns a point of order lcm(m, r) from P of order m and R of order r:
+// for each prime q, the q-part of the one with the highest power of q is kept, the q-parts having coprime orders.
+func (ec *EllipticCurve) combineOrders(P *Point, m *big.Int, R *Point, r *big.Int, factors []utils.PrimePower) (*Point, *big.Int) {
+	var res *Point
+	order := big.NewInt(1)
+	for _, pp := range factors {
+		vm, vr := valuation(m, pp.P), valuation(r, pp.P)
+		X, x, v := P, m, vm
+		if vr > vm {
+			X, x, v = R, r, vr
+		}
+		qv := new(big.Int).Exp(pp.P, big.NewInt(int64(v)), nil)
+		res = ec.add(res, ec.mul(X, new(big.Int).Quo(x, qv))) // q-part, of order q^v
+		order.Mul(order, qv)
+	}
+	return res, order
+}
+
+// independentPoint Returns a point S of order q^a such as <S> ∩ <P2> = {O}, q^e being the power of q in N and
+// q^b = q^(e-a) its power in N2. With R_q = [N/q^e]R the q-part of a random point R, [q^a]R_q = [k]P2_q,
+// P2_q = [N2/q^b]P2, with q^a | k when P2 has maximal order: S = R_q - [k/q^a]P2_q is killed by q^a,
+// and independent of P2 iff [q^(a-1)]S is not in the subgroup of order q of <P2>.
+func (ec *EllipticCurve) independentPoint(N *big.Int, pp utils.PrimePower, a int, P2 *Point, N2 *big.Int) (*Point, bool) {
+	q := pp.P
+	b := pp.E - a
+	qa := new(big.Int).Exp(q, big.NewInt(int64(a)), nil)
+	qb := new(big.Int).Exp(q, big.NewInt(int64(b)), nil)
+	qe := new(big.Int).Exp(q, big.NewInt(int64(pp.E)), nil)
+	P2q := ec.mul(P2, new(big.Int).Quo(N2, qb))
+	torsionP2 := ec.mul(P2q, new(big.Int).Quo(qb, q)) // generates the subgroup of order q of <P2>
+
+	for draw := 0; draw < pointDraws; draw++ {
+		Rq := ec.mul(ec.RandomPoint(), new(big.Int).Quo(N, qe))
+		k, ok := ec.dlogPrimePower(P2q, ec.mul(Rq, qa), q, b, ec.bsgs)
+		if !ok || new(big.Int).Mod(k, qa).Sign() != 0 {
+			continue
+		}
+		S := ec.add(Rq, ec.neg(ec.mul(P2q, new(big.Int).Quo(k, qa))))
+
+		torsionS := ec.mul(S, new(big.Int).Quo(qa, q))
+		if torsionS == nil {
+			continue // order of S < q^a
+		}
+		if _, dependent := ec.bsgs(torsionP2, torsionS, q); dependent {
+			continue
+		}
+		return S, true
+	}
+	return nil, false
+}
+
+// Order Returns the exact order of the point P of the curve, N being the number of points of the curve (or any multiple
+// of the order of P): N is factored, then its prime factors are removed one by one as long as the multiple still kills P.
+// Fails if N cannot be fully factored, or if [N]P != O.
+func (ec *EllipticCurve) Order(P *Point, N *big.Int) (*big.Int, error) {
+	factors, ok := utils.Factorize(N)
+	if !ok {
+		return nil, fmt.Errorf("Could not factor %s.\n", N)
+	}
+	return ec.OrderWithFactors(P, N, factors)
+}
+
+// OrderWithFactors Same as Order, with the factorization of N already known (see utils.Factorize).
+func (ec *EllipticCurve) OrderWithFactors(P *Point, N *big.Int, factors []utils.PrimePower) (*big.Int, error) {
+	if !ec.PointIsOnCurve(P) {
+		return nil, fmt.Errorf("Given point p(%s,%s) is not on the curve.\n", P.x, P.y)
+	}
+	if ec.mul(P, N) != nil {
+		return nil, fmt.Errorf("[%s]%s != O, %s is not a multiple of the order of the point.\n", N, P, N)
+	}
+	return ec.orderOf(P, N, factors), nil
+}
+
+// RandomPointOfOrder Returns a random point of order exactly d, d dividing the number of points N of the curve.
+// It is built prime by prime: for q^f || d, the q-part [N/q^v]R of random points R (q^v || N) is drawn until its order q^s
+// is at least q^f, then multiplied by q^(s-f). Fails if d does not divide N, or if no point of order d was met
+// (there is none when d does not divide the exponent N2 of the group, see GroupStructure).
+func (ec *EllipticCurve) RandomPointOfOrder(d, N *big.Int) (*Point, error) {
+	if d.Sign() <= 0 || new(big.Int).Mod(N, d).Sign() != 0 {
+		return nil, fmt.Errorf("%s does not divide the number of points %s.\n", d, N)
+	}
+	factors, ok := utils.Factorize(d)
+	if !ok {
+		return nil, fmt.Errorf("Could not factor %s.\n", d)
+	}
+
+	var res *Point
+	for _, pp := range factors {
+		v := valuation(N, pp.P)
+		qv := new(big.Int).Exp(pp.P, big.NewInt(int64(v)), nil)
+		cofactor := new(big.Int).Quo(N, qv)
+		found := false
+		for draw := 0; draw < 4*pointDraws && !found; draw++ {
+			Rq := ec.mul(ec.RandomPoint(), cofactor)
+			if ec.mul(Rq, qv) != nil {
+				return nil, fmt.Errorf("%s is not the number of points: [N]R != O.\n", N)
+			}
+			// R_q has order q^s, [q^(s-f)]R_q then has order q^f
+			s := valuation(ec.orderOf(Rq, qv, []utils.PrimePower{{P: pp.P, E: v}}), pp.P)
+			if s < pp.E {
+				continue
+			}
+			res = ec.add(res, ec.mul(Rq, new(big.Int).Exp(pp.P, big.NewInt(int64(s-pp.E)), nil)))
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("No point of order %s found.\n", d)
+		}
+	}
+	return res, nil
+}
+
+// orderOf Returns the order of P, knowing that [N]P = O and the factorization of N:
+// each prime is removed from N as long as the multiple still kills P.
+func (ec *EllipticCurve) orderOf(P *Point, N *big.Int, factors []utils.PrimePower) *big.Int {
+	order := new(big.Int).Set(N)
+	for _, pp := range factors {
+		for i := 0; i < pp.E; i++ {
+			candidate := new(big.Int).Quo(order, pp.P)
+			if ec.mul(P, candidate) != nil {
+				break
+			}
+			order = candidate
+		}
+	}
+	return order
+}
+
+// valuation Returns the exponent of the prime q in n (n != 0)
+func valuation(n, q *big.Int) int {
+	v := 0
+	rest := new(big.Int).Set(n)
+	r := new(big.Int)
+	for {
+		quo, rem := new(big.Int).QuoRem(rest, q, r)
+		if rem.Sign() != 0 {
+			return v
+		}
+		rest = quo
+		v++
+	}
+}
+
+// add Returns P + Q, both being points of the (non singular) curve
+func (ec *EllipticCurve) add(P, Q *Point) *Point {
+	R, err := ec.SumPointsOnCurve(P, Q)
+	if err != nil {
+		panic(err)
+	}
+	return R
+}
+
+// mul Returns [k]P (k >= 0), P being a point of the curve; k is left untouched
+func (ec *EllipticCurve) mul(P *Point, k *big.Int) *Point {
+	R, err := ec.MultiplyPointByScalar(P, new(big.Int).Set(k))
+	if err != nil {
+		panic(err)
+	}
+	return R
+}
+
+// neg Returns -P = (x, -y)
+func (ec *EllipticCurve) neg(P *Point) *Point {
+	if P == nil {
+		return nil
+	}
+	y := new(big.Int).Sub(ec.p, P.y)
+	return &Point{new(big.Int).Set(P.x), y.Mod(y, ec.p)}
+}
@@ -0,0 +1,136 @@
+package ec_test
+
+import (
+	"goschoof/ec"
+	"goschoof/utils"
+	"math/big"
+	"testing"
+)
+
+// enumeratePoints Returns all the affine points of the curve, testing all the (x, y), O(p²)
+func enumeratePoints(t *testing.T, curve *ec.EllipticCurve) []*ec.Point {
+	t.Helper()
+	p := curve.GetP().Int64()
+	var points []*ec.Point
+	for x := int64(0); x < p; x++ {
+		for y := int64(0); y < p; y++ {
+			P, err := ec.NewPoint(big.NewInt(x), big.NewInt(y))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if curve.PointIsOnCurve(P) {
+				points = append(points, P)
+			}
+		}
+	}
+	return points
+}
+
+// orderByAddition Returns the order of P, adding P until the omega is reached
+func orderByAddition(t *testing.T, curve *ec.EllipticCurve, P *ec.Point) int64 {
+	t.Helper()
+	R := P
+	order := int64(1)
+	for R != nil {
+		var err error
+		if R, err = curve.SumPointsOnCurve(R, P); err != nil {
+			t.Fatal(err)
+		}
+		order++
+	}
+	return order
+}
+
+// TestGroupStructure compares GroupStructure, Order and OrderWithFactors with the orders of all the points
+func TestGroupStructure(t *testing.T) {
+	cases := []struct {
+		a, b, p int64
+		n1, n2  int64 // E(F_p) ≅ Z/n1 × Z/n2
+	}{
+		{3, 5, 101, 1, 115},
+		{1, 1, 101, 1, 105},
+		{1, 0, 103, 1, 104}, // supersingular, x³ + x having the single root 0
+		{2, 3, 97, 2, 50},
+		{12, 0, 13, 2, 4}, // y² = x³ - x, full 2-torsion
+		{1, 0, 73, 4, 20}, // y² = x³ + x, p = 1 mod 4
+		{60, 0, 61, 6, 12},
+		{0, 1, 31, 6, 6},
+		{0, 2, 73, 9, 9},
+		{0, 9, 37, 3, 9},
+		{0, 5, 61, 3, 21},
+	}
+	for _, c := range cases {
+		curve := newCurve(t, c.a, c.b, c.p)
+		points := enumeratePoints(t, curve)
+		N := big.NewInt(int64(len(points)) + 1)
+		factors, ok := utils.Factorize(N)
+		if !ok {
+			t.Fatalf("could not factor %s", N)
+		}
+
+		exponent := int64(1)
+		for _, P := range points {
+			want := orderByAddition(t, curve, P)
+			exponent = max(exponent, want)
+			if got, err := curve.Order(P, N); err != nil || got.Int64() != want {
+				t.Errorf("y² = x³ + %dx + %d mod %d: Order(%s) = %v, %v, want %d", c.a, c.b, c.p, P, got, err, want)
+			}
+			if got, err := curve.OrderWithFactors(P, N, factors); err != nil || got.Int64() != want {
+				t.Errorf("y² = x³ + %dx + %d mod %d: OrderWithFactors(%s) = %v, %v, want %d", c.a, c.b, c.p, P, got, err, want)
+			}
+		}
+		if n1 := N.Int64() / exponent; n1 != c.n1 || exponent != c.n2 {
+			t.Fatalf("y² = x³ + %dx + %d mod %d: Z/%d × Z/%d by enumeration, want Z/%d × Z/%d", c.a, c.b, c.p, n1, exponent, c.n1, c.n2)
+		}
+
+		s, err := curve.GroupStructure(N)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.N1.Int64() != c.n1 || s.N2.Int64() != c.n2 {
+			t.Errorf("GroupStructure(y² = x³ + %dx + %d mod %d) = %s, want Z/%d × Z/%d", c.a, c.b, c.p, s, c.n1, c.n2)
+			continue
+		}
+		if got := orderByAddition(t, curve, s.P2); got != c.n2 {
+			t.Errorf("y² = x³ + %dx + %d mod %d: P2 = %s of order %d, want %d", c.a, c.b, c.p, s.P2, got, c.n2)
+		}
+		if c.n1 == 1 && s.P1 != nil {
+			t.Errorf("y² = x³ + %dx + %d mod %d: P1 = %s for a cyclic group", c.a, c.b, c.p, s.P1)
+		}
+		if c.n1 > 1 && orderByAddition(t, curve, s.P1) != c.n1 {
+			t.Errorf("y² = x³ + %dx + %d mod %d: P1 = %s not of order %d", c.a, c.b, c.p, s.P1, c.n1)
+		}
+
+		// the [i]P1 + [j]P2 are all the points, each one once
+		elements, err := s.Elements(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen := make(map[string]bool)
+		for _, E := range elements {
+			seen[E.String()] = true
+		}
+		if int64(len(seen)) != N.Int64() {
+			t.Errorf("y² = x³ + %dx + %d mod %d: %s generates %d distinct points, want %s", c.a, c.b, c.p, s, len(seen), N)
+		}
+		for _, P := range points {
+			if !seen[P.String()] {
+				t.Errorf("y² = x³ + %dx + %d mod %d: %s not generated by %s", c.a, c.b, c.p, P, s)
+			}
+		}
+	}
+}
+
+func TestGroupStructureErrors(t *testing.T) {
+	curve := newCurve(t, 2, 3, 97) // 100 points
+	if _, err := curve.GroupStructure(big.NewInt(101)); err == nil {
+		t.Error("GroupStructure accepted a wrong number of points")
+	}
+	P := curve.RandomPoint()
+	if _, err := curve.Order(P, big.NewInt(7)); err == nil {
+		t.Error("Order accepted a multiple not killing the point")
+	}
+	if _, err := newCurve(t, 0, 0, 97).GroupStructure(big.NewInt(97)); err == nil {
+		t.Error("GroupStructure accepted a singular curve")
+	}
+}
@@ -0,0 +1,101 @@
+package ec
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// hasseInvariantMaxP the largest p for which the Hasse invariant is computed (O(p) operations)
+const hasseInvariantMaxP = 1 << 24
+
+// SupersingularDraws number of random points checked by IsSupersingular above hasseInvariantMaxP,
+// see IsSupersingularRandom
+const SupersingularDraws = 40
+
+// IsSupersingular True iff the curve is supersingular, i.e. has no point of order p over the algebraic closure,
+// without computing its trace. For p <= 2^24 the Hasse invariant is computed, see HasseInvariant, above the
+// randomized test of IsSupersingularRandom is used with SupersingularDraws points.
+func (ec *EllipticCurve) IsSupersingular() bool {
+	if ec.p.Cmp(big.NewInt(hasseInvariantMaxP)) <= 0 {
+		h, _ := ec.HasseInvariant()
+		return h.Sign() == 0
+	}
+	return ec.IsSupersingularRandom(SupersingularDraws)
+}
+
+// SupersingularRandomMinP the smallest p for which the randomized test of IsSupersingularRandom is exact
+const SupersingularRandomMinP = 37
+
+// IsSupersingularRandom True iff [p + 1]P = O for draws random points P of the curve. A supersingular curve has
+// p + 1 points, so it always passes. If all the points of an ordinary curve, of group Z/n1 × Z/n2 (n1 | n2, n1 | p - 1),
+// were killed by p + 1, n2 would divide p + 1 and n1 | gcd(p - 1, p + 1) = 2, so N = (p + 1)/k or 2(p + 1)/k, k >= 2
+// (N = p + 1 being supersingular): for p >= SupersingularRandomMinP none of them is in the Hasse interval, while e.g.
+// y² = x³ + 4x + 5 mod 17 has 12 = 2·18/3 points of exponent 6 | 18. The points killed by p + 1 then form a proper
+// subgroup, each random point revealing it with probability at least 1/2: the answer is wrong with probability
+// at most 2^-draws, and only for an ordinary curve. Below SupersingularRandomMinP the Hasse invariant answers instead.
+// Costs O(draws·log(p)) group operations, an ordinary curve being most often rejected by the first point.
+func (ec *EllipticCurve) IsSupersingularRandom(draws int) bool {
+	if ec.p.Cmp(big.NewInt(SupersingularRandomMinP)) < 0 {
+		h, _ := ec.HasseInvariant()
+		return h.Sign() == 0
+	}
+	pPlus1 := new(big.Int).Add(ec.p, big.NewInt(1))
+	for i := 0; i < draws; i++ {
+		if ec.mul(ec.RandomPoint(), pPlus1) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSupersingularTrace True iff the curve of trace t (e.g. schoof.Schoof(curve).T) is supersingular: t = 0 mod p.
+func (ec *EllipticCurve) IsSupersingularTrace(t *big.Int) bool {
+	return new(big.Int).Mod(t, ec.p).Sign() == 0
+}
+
+// HasseInvariant Returns the Hasse invariant of the curve, the coefficient of x^(p-1) in (x³ + ax + b)^((p-1)/2)
+// mod p, which is t mod p: the curve is supersingular iff it is 0. Only for p <= 2^24.
+// The coefficients g_n of g = f^m, f = x³ + ax + b, m = (p-1)/2, follow from f·g' = m·f'·g:
+// b(n+1)·g_(n+1) = a(m-n)·g_n + (3m-n+2)·g_(n-2), with g_0 = b^m; to avoid the divisions h_n = b^n·n!·g_n is computed,
+// h_(n+1) = a(m-n)·h_n + (3m-n+2)·b²·n(n-1)·h_(n-2), and g_(p-1) = -h_(p-1) as b^(p-1) = 1 and (p-1)! = -1.
+func (ec *EllipticCurve) HasseInvariant() (*big.Int, error) {
+	if ec.p.Cmp(big.NewInt(hasseInvariantMaxP)) > 0 {
+		return nil, fmt.Errorf("p %s too big to compute the Hasse invariant.\n", ec.p)
+	}
+	p := ec.p.Int64()
+	a := new(big.Int).Mod(ec.a, ec.p).Int64()
+	b := new(big.Int).Mod(ec.b, ec.p).Int64()
+	m := (p - 1) / 2
+
+	if b == 0 {
+		// f^m = x^m (x² + a)^m: the coefficient of x^m in (x² + a)^m, C(m, m/2)·a^(m/2), 0 for an odd m
+		if m%2 == 1 {
+			return big.NewInt(0), nil
+		}
+		binomial := big.NewInt(1)
+		for i := int64(1); i <= m/2; i++ {
+			binomial.Mul(binomial, big.NewInt(m/2+i))
+			binomial.Mul(binomial, new(big.Int).ModInverse(big.NewInt(i), ec.p))
+			binomial.Mod(binomial, ec.p)
+		}
+		aPow := new(big.Int).Exp(big.NewInt(a), big.NewInt(m/2), ec.p)
+		return binomial.Mul(binomial, aPow).Mod(binomial, ec.p), nil
+	}
+
+	mod := func(x int64) int64 {
+		x %= p
+		if x < 0 {
+			x += p
+		}
+		return x
+	}
+	b2 := b * b % p
+	// h_(n-2), h_(n-1), h_n
+	h2, h1, h := int64(0), int64(0), new(big.Int).Exp(big.NewInt(b), big.NewInt(m), ec.p).Int64()
+	for n := int64(0); n < p-1; n++ {
+		next := mod(a*mod(m-n)) * h % p
+		next += mod(3*m-n+2) * b2 % p * (n * mod(n-1) % p) % p * h2 % p
+		h2, h1, h = h1, h, next%p
+	}
+	return big.NewInt(mod(-h)), nil
+}
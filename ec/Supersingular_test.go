@@ -0,0 +1,76 @@
+package ec_test
+
+import (
+	"goschoof/ec"
+	"goschoof/schoof"
+	"math/big"
+	"testing"
+)
+
+// TestHasseInvariant compares the Hasse invariant with t mod p, t = p + 1 - N counted naively
+func TestHasseInvariant(t *testing.T) {
+	for _, p := range []int64{5, 7, 11, 13, 23, 59, 97, 101, 103, 211, 1009} {
+		for a := int64(0); a < 4; a++ {
+			for b := int64(0); b < 4; b++ {
+				if (4*a*a*a+27*b*b)%p == 0 {
+					continue // singular
+				}
+				curve := newCurve(t, a, b, p)
+				N, err := ec.CountPointsNaive(curve)
+				if err != nil {
+					t.Fatal(err)
+				}
+				trace := new(big.Int).Sub(big.NewInt(p+1), N)
+				want := trace.Mod(trace, big.NewInt(p))
+				got, err := curve.HasseInvariant()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Cmp(want) != 0 {
+					t.Errorf("HasseInvariant(y² = x³ + %dx + %d mod %d) = %s, want t mod p = %s", a, b, p, got, want)
+				}
+				if curve.IsSupersingular() != (want.Sign() == 0) {
+					t.Errorf("IsSupersingular(y² = x³ + %dx + %d mod %d) disagrees with t mod p = %s", a, b, p, want)
+				}
+			}
+		}
+	}
+
+	if _, err := ec.CreateEC().HasseInvariant(); err == nil {
+		t.Error("HasseInvariant accepted a 256 bits p")
+	}
+}
+
+func TestIsSupersingularRandom(t *testing.T) {
+	cases := []struct {
+		a, b, p int64
+		want    bool
+	}{
+		{1, 0, 1019, true}, // y² = x³ + x, p = 3 mod 4
+		{0, 1, 1013, true}, // y² = x³ + 1, p = 2 mod 3
+		{1, 1, 1019, false},
+		{1, 7, 17, false},        // 12 points, Z/2 × Z/6, killed by p + 1 = 18 although ordinary
+		{4, 5, 17, false},        // idem
+		{1, 0, 2147483647, true}, // p = 2^31 - 1, above the Hasse invariant limit
+		{1, 1, 2147483647, false},
+	}
+	for _, c := range cases {
+		curve := newCurve(t, c.a, c.b, c.p)
+		if got := curve.IsSupersingularRandom(ec.SupersingularDraws); got != c.want {
+			t.Errorf("IsSupersingularRandom(y² = x³ + %dx + %d mod %d) = %t, want %t", c.a, c.b, c.p, got, c.want)
+		}
+		if got := curve.IsSupersingular(); got != c.want {
+			t.Errorf("IsSupersingular(y² = x³ + %dx + %d mod %d) = %t, want %t", c.a, c.b, c.p, got, c.want)
+		}
+	}
+}
+
+func TestSchoofSupersingular(t *testing.T) {
+	res := schoof.Schoof(newCurve(t, 1, 0, 1000003))
+	if res.Method != schoof.MethodSupersingular || res.N.Int64() != 1000004 {
+		t.Errorf("Schoof(y² = x³ + x mod 1000003): method %s, N = %s", res.Method, res.N)
+	}
+	if res := schoof.Schoof(newCurve(t, 3, 5, 1000003)); res.Method == schoof.MethodSupersingular {
+		t.Errorf("Schoof(y² = x³ + 3x + 5 mod 1000003) answered as supersingular")
+	}
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
+	"goschoof/cm"
 	"goschoof/ec"
 	"goschoof/polynom"
 	"goschoof/schoof"
 	"goschoof/utils"
 	"log"
+	"log/slog"
 	"math/big"
 )
 
@@ -18,7 +21,7 @@ func main() {
 	//secp256k1 curve
 	curve := ec.CreateEC()
 
-	log.Printf("Successfully created 'curve' EllipticCurve: (addresses) %x \n", curve)
+	log.Printf("Successfully created 'curve' EllipticCurve: (addresses) %p \n", curve)
 
 	log.Printf("Checking that the neutral point (nil) is on the curve: %t\n", curve.PointIsOnCurve(nil))
 
@@ -36,7 +39,7 @@ func main() {
 
 	res, err := curve.MultiplyPointByScalar(gen, big.NewInt(2))
 	if err != nil {
-		log.Fatalf(err.Error())
+		log.Fatal(err)
 	}
 	log.Printf("Result of the addition of multiplying our 'gen' by 2: \n(%s,\n%s)\n", res.GetX(), res.GetY())
 
@@ -60,7 +63,7 @@ func main() {
 
 	curve2, err := ec.NewEllipticCurve(a, b, p)
 	if err != nil {
-		log.Fatalf(err.Error())
+		log.Fatal(err)
 	}
 
 	points := schoof.CountTorsion2PointsFromPoly(curve2)
@@ -115,6 +118,61 @@ func main() {
 	// SCHOOF 		//
 	//////////////////
 
-	N := schoof.Schoof(curve2)
-	log.Printf("N found for secp256k1: %v", N)
+	// progress of each l as structured events, silent without observer
+	ctx := schoof.WithObserver(context.Background(), schoof.SlogObserver(slog.Default()))
+	count, err := schoof.SchoofContext(ctx, curve2)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("N found for secp256k1: %v (t = %v, verified: %t, unique: %t)",
+		count.N, count.T, count.Verification.Verified(), count.Verification.Unique)
+
+	hasse, err := curve2.CheckHasseTheorem(schoof.CountPoints)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Hasse theorem: %s", hasse)
+
+	structure, err := curve2.GroupStructure(count.N)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Group structure: %s", structure)
+
+	for _, pt := range points3 {
+		order, err := curve2.Order(pt, count.N)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Order of the point %s: %s", pt, order)
+	}
+
+	// discrete logarithm of [42]P2 in base P2, the generator of the largest cyclic factor
+	target, err := curve2.MultiplyPointByScalar(structure.P2, big.NewInt(42))
+	if err != nil {
+		log.Fatal(err)
+	}
+	dlog, err := curve2.PohligHellman(context.Background(), structure.P2, target, schoof.CountPoints)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Discrete logarithm of %s in base %s: %s (mod %s)", target, structure.P2, dlog, structure.N2)
+
+	security, err := curve2.CheckSecurity(schoof.CountPoints)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Security checks: %s", security)
+	if err := security.Err(); err != nil {
+		log.Printf("Curve rejected: %v", err)
+	}
+	log.Printf("Supersingular (Hasse invariant): %t", curve2.IsSupersingular())
+
+	// a curve mod 2^64 + 13 with a prime number of points, by complex multiplication
+	cmP, _ := new(big.Int).SetString("18446744073709551629", 10)
+	cmCurve, err := cm.Generate(cmP, 10000, func(N *big.Int) bool { return N.ProbablyPrime(20) })
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("CM curve: %s", cmCurve)
 }
@@ -0,0 +1,64 @@
+package polynom
+
+import (
+	"math/big"
+)
+
+// Compose returns f(g) mod h, with Brent-Kung baby-step/giant-step modular composition:
+// with m = ceil(sqrt(deg(f)+1)), the baby steps g^0..g^(m-1) mod h and the giant step G = g^m mod h
+// are precomputed, then f = sum(f_j(x) x^(jm)) (deg(f_j) < m) is evaluated as sum(f_j(g) G^j)
+// with Horner's method on G. This needs about 2*sqrt(deg(f)) multiplications mod h instead of deg(f).
+func (m *Modulus) Compose(f, g *Polynom) *Polynom {
+	p := m.h.P
+	deg := f.Degree()
+	if deg == 0 {
+		return m.Reduce(f)
+	}
+
+	steps := 1
+	for steps*steps < deg+1 {
+		steps++
+	}
+
+	// baby steps: g^i mod h, i < steps
+	baby := make([]*Polynom, steps)
+	baby[0] = NewPolynom([]*big.Int{big.NewInt(1)}, p)
+	gr := m.Reduce(g)
+	for i := 1; i < steps; i++ {
+		baby[i] = m.MulMod(baby[i-1], gr)
+	}
+	giant := m.MulMod(baby[steps-1], gr) // g^steps mod h
+
+	// Horner on the giant step, from the highest block
+	res := NewPolynom([]*big.Int{big.NewInt(0)}, p)
+	for j := deg / steps; j >= 0; j-- {
+		block := NewPolynom([]*big.Int{big.NewInt(0)}, p)
+		for i := 0; i < steps; i++ {
+			c := f.Coeff(j*steps + i)
+			if c.Sign() == 0 {
+				continue
+			}
+			block = block.Add(baby[i].Copy().Scale(c))
+		}
+		res = m.MulMod(res, giant).Add(block)
+	}
+	return res
+}
+
+// Frobenius returns x^(p^k) mod h from xp = x^p mod h, by composition only:
+// since h(x^p) = h(x)^p, x^(p^(i+j)) = x^(p^i)(x^(p^j)) mod h, so x^(p^k) is
+// obtained with O(log k) compositions instead of k exponentiations by p.
+func (m *Modulus) Frobenius(xp *Polynom, k int) *Polynom {
+	res := NewPolynom([]*big.Int{big.NewInt(0), big.NewInt(1)}, m.h.P) // x^(p^0)
+	res = m.Reduce(res)
+	pow := m.Reduce(xp) // x^(p^(2^i))
+	for ; k > 0; k >>= 1 {
+		if k&1 == 1 {
+			res = m.Compose(res, pow)
+		}
+		if k > 1 {
+			pow = m.Compose(pow, pow)
+		}
+	}
+	return res
+}
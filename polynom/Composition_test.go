@@ -0,0 +1,63 @@
+package polynom
+
+import (
+	"math/big"
+	"testing"
+)
+
+// composeHorner Returns f(g) mod h with Horner's method, one multiplication per coefficient of f
+func composeHorner(f, g, h *Polynom) *Polynom {
+	res := NewPolynom([]*big.Int{big.NewInt(0)}, f.P)
+	for i := f.Degree(); i >= 0; i-- {
+		res = remainder(res.Mul(g).Add(NewPolynom([]*big.Int{f.Coeff(i)}, f.P)), h)
+	}
+	return res
+}
+
+func TestCompose(t *testing.T) {
+	for _, p := range []*big.Int{big.NewInt(101), secp256k1P} {
+		for _, n := range []int{1, 4, 24} {
+			h := randomPolynom(n, p)
+			m := NewModulus(h)
+			// f of degree 0 (a constant), below, equal to and above a perfect square minus one, and above deg(h)
+			for _, deg := range []int{0, 1, 3, 8, 9, 2*n + 5} {
+				f, g := randomPolynom(deg, p), randomPolynom(n+3, p)
+				if got, want := m.Compose(f, g), composeHorner(f, g, h); !got.Equals(want) {
+					t.Fatalf("deg h = %d mod %s: Compose of degree %d = %s, want %s", n, p, deg, got, want)
+				}
+			}
+			// f(x) = f, x(g) = g
+			f := randomPolynom(n+1, p)
+			x := NewPolynom([]*big.Int{big.NewInt(0), big.NewInt(1)}, p)
+			if !m.Compose(f, x).Equals(m.Reduce(f)) || !m.Compose(x, f).Equals(m.Reduce(f)) {
+				t.Errorf("deg h = %d mod %s: composing with x is not the identity", n, p)
+			}
+		}
+	}
+}
+
+// TestFrobenius compares x^(p^k) by composition with the exponentiation by p^k
+func TestFrobenius(t *testing.T) {
+	for _, p := range []*big.Int{big.NewInt(101), secp256k1P} {
+		h := randomPolynom(12, p)
+		m := NewModulus(h)
+		x := NewPolynom([]*big.Int{big.NewInt(0), big.NewInt(1)}, p)
+		xp := m.PowMod(x, p)
+		for k := 0; k <= 6; k++ {
+			pk := new(big.Int).Exp(p, big.NewInt(int64(k)), nil)
+			if got, want := m.Frobenius(xp, k), m.PowMod(x, pk); !got.Equals(want) {
+				t.Errorf("x^(%s^%d) mod h = %s, want %s", p, k, got, want)
+			}
+		}
+	}
+
+	// x^(p^k) = x mod h iff the degrees of the irreducible factors of h (square free) divide k:
+	// x^4 - 4 = (x² - 2)(x² + 2), ±2 not being squares mod 101
+	h := poly(101, 97, 0, 0, 0, 1)
+	m := NewModulus(h)
+	x := poly(101, 0, 1)
+	xp := m.PowMod(x, big.NewInt(101))
+	if m.Frobenius(xp, 1).Equals(x) || !m.Frobenius(xp, 2).Equals(x) {
+		t.Errorf("x^(101^k) mod x^4 - 4: %s for k = 1, %s for k = 2", m.Frobenius(xp, 1), m.Frobenius(xp, 2))
+	}
+}
@@ -0,0 +1,338 @@
+package polynom
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"sort"
+)
+
+// Factor An irreducible monic factor of a polynom, with its multiplicity.
+type Factor struct {
+	Poly         *Polynom
+	Multiplicity int
+}
+
+// DegreeFactor A product of all the irreducible factors of a given degree,
+// as returned by the distinct-degree factorization.
+type DegreeFactor struct {
+	Poly   *Polynom
+	Degree int
+}
+
+// Derivative returns the formal derivative of the polynom mod p.
+func (poly *Polynom) Derivative() *Polynom {
+	deg := poly.Degree()
+	if deg == 0 {
+		return NewPolynom([]*big.Int{big.NewInt(0)}, poly.P)
+	}
+	coeffs := make([]*big.Int, deg)
+	for i := 1; i <= deg; i++ {
+		// d/dx c_i x^i = i * c_i x^(i-1)
+		coeffs[i-1] = new(big.Int).Mul(poly.Coeff(i), big.NewInt(int64(i)))
+	}
+	return NewPolynom(coeffs, poly.P)
+}
+
+// Monic returns a copy of the polynom divided by its leading coefficient.
+// The zero polynom is returned as is.
+func (poly *Polynom) Monic() *Polynom {
+	if poly.IsZero() {
+		return poly.Copy()
+	}
+	hn, _ := poly.NormalizeMonic()
+	return hn
+}
+
+// isOne true iff the polynom is the constant 1
+func (poly *Polynom) isOne() bool {
+	return poly.Degree() == 0 && poly.Coeff(0).Cmp(big.NewInt(1)) == 0
+}
+
+// Factor returns the complete factorization of the polynom over F_p, as a list of
+// monic irreducible factors with their multiplicities, sorted by degree.
+// The leading coefficient is not part of the result (see LeadingCoeff).
+// Constant (and zero) polynoms have no factors.
+//
+// Uses square-free decomposition, then distinct-degree and equal-degree factorization (Cantor-Zassenhaus).
+func (poly *Polynom) Factor() []Factor {
+	factors, _ := poly.FactorContext(context.Background())
+	return factors
+}
+
+// FactorContext Same as Factor, checking for the cancellation of ctx between the factorization steps.
+// Returns ctx.Err() if cancelled.
+func (poly *Polynom) FactorContext(ctx context.Context) ([]Factor, error) {
+	var factors []Factor
+	if poly.Degree() == 0 {
+		return factors, nil
+	}
+
+	for _, sf := range poly.SquareFreeFactorization() {
+		ddfs, err := sf.Poly.DistinctDegreeFactorizationContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, ddf := range ddfs {
+			irrs, err := ddf.Poly.EqualDegreeFactorizationContext(ctx, ddf.Degree)
+			if err != nil {
+				return nil, err
+			}
+			for _, irr := range irrs {
+				factors = append(factors, Factor{Poly: irr, Multiplicity: sf.Multiplicity})
+			}
+		}
+	}
+
+	sortFactors(factors)
+	return factors, nil
+}
+
+// SquareFreeFactorization returns the square-free decomposition of the (monic) polynom:
+// poly = lc * prod(f_i ^ i), each f_i being square-free and pairwise coprime.
+// Takes into consideration that in characteristic p, f' can vanish (f being a p-th power).
+func (poly *Polynom) SquareFreeFactorization() []Factor {
+	var factors []Factor
+	f := poly.Monic()
+	f.trimTrailingZeros()
+	if f.Degree() == 0 {
+		return factors
+	}
+
+	i := 1
+	c := GCDPolynom(f, f.Derivative())
+	w := DivExact(f, c)
+
+	// w holds the product of all the factors with multiplicity >= i (and not divisible by p)
+	for !w.isOne() {
+		y := GCDPolynom(w, c)
+		fac := DivExact(w, y)
+		if !fac.isOne() {
+			factors = append(factors, Factor{Poly: fac, Multiplicity: i})
+		}
+		w = y
+		c = DivExact(c, y)
+		i++
+	}
+
+	// what remains is a p-th power: c = g^p
+	if !c.isOne() {
+		if !c.P.IsInt64() {
+			panic("SquareFreeFactorization: p-th root of a polynom of degree >= p")
+		}
+		p := int(c.P.Int64())
+		g := c.pthRoot()
+		for _, sf := range g.SquareFreeFactorization() {
+			factors = append(factors, Factor{Poly: sf.Poly, Multiplicity: sf.Multiplicity * p})
+		}
+	}
+	return factors
+}
+
+// pthRoot returns g such as g^p = poly, poly having only coefficients at multiples of p.
+// Since a^p = a in F_p, g coefficients are the ones of poly at indexes i*p.
+func (poly *Polynom) pthRoot() *Polynom {
+	p := int(poly.P.Int64())
+	deg := poly.Degree()
+	coeffs := make([]*big.Int, deg/p+1)
+	for i := range coeffs {
+		coeffs[i] = poly.Coeff(i * p)
+	}
+	return NewPolynom(coeffs, poly.P)
+}
+
+// DistinctDegreeFactorization splits a monic square-free polynom into products of
+// irreducible factors sharing the same degree, using gcd(f, x^(p^i) - x).
+func (poly *Polynom) DistinctDegreeFactorization() []DegreeFactor {
+	factors, _ := poly.DistinctDegreeFactorizationContext(context.Background())
+	return factors
+}
+
+// DistinctDegreeFactorizationContext Same as DistinctDegreeFactorization, checking for the cancellation
+// of ctx at each degree i. Returns ctx.Err() if cancelled.
+func (poly *Polynom) DistinctDegreeFactorizationContext(ctx context.Context) ([]DegreeFactor, error) {
+	var factors []DegreeFactor
+	f := poly.Monic()
+	f.trimTrailingZeros()
+	x := NewPolynom([]*big.Int{big.NewInt(0), big.NewInt(1)}, poly.P)
+
+	// h = x^(p^i) mod f, obtained by composing with x^p at each step
+	mod := NewModulus(f)
+	xp, err := mod.PowModContext(ctx, x, poly.P)
+	if err != nil {
+		return nil, err
+	}
+	h := xp.Copy()
+	for i := 1; f.Degree() >= 2*i; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if i > 1 {
+			h = mod.Compose(h, xp)
+		}
+		g := GCDPolynom(f, h.Sub(x))
+		if !g.isOne() {
+			factors = append(factors, DegreeFactor{Poly: g, Degree: i})
+			f = DivExact(f, g)
+			mod = NewModulus(f)
+			h = mod.Reduce(h)
+			xp = mod.Reduce(xp)
+		}
+	}
+	if f.Degree() > 0 {
+		factors = append(factors, DegreeFactor{Poly: f, Degree: f.Degree()})
+	}
+	return factors, nil
+}
+
+// EqualDegreeFactorization splits a monic square-free polynom whose irreducible factors
+// all have degree d, using the probabilistic Cantor-Zassenhaus algorithm.
+func (poly *Polynom) EqualDegreeFactorization(d int) []*Polynom {
+	factors, _ := poly.EqualDegreeFactorizationContext(context.Background(), d)
+	return factors
+}
+
+// EqualDegreeFactorizationContext Same as EqualDegreeFactorization, checking for the cancellation
+// of ctx before each splitting attempt. Returns ctx.Err() if cancelled.
+func (poly *Polynom) EqualDegreeFactorizationContext(ctx context.Context, d int) ([]*Polynom, error) {
+	f := poly.Monic()
+	f.trimTrailingZeros()
+	n := f.Degree()
+	if n == 0 {
+		return nil, nil
+	}
+	if n <= d {
+		return []*Polynom{f}, nil
+	}
+
+	for {
+		g, err := f.splitEqualDegree(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		if g == nil {
+			continue
+		}
+		left, err := g.EqualDegreeFactorizationContext(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		right, err := DivExact(f, g).EqualDegreeFactorizationContext(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		return append(left, right...), nil
+	}
+}
+
+// splitEqualDegree tries once to find a proper factor of the polynom, with a random polynom a:
+// gcd(a^((p^d - 1) / 2) - 1, f) for odd p, or gcd(a + a^2 + ... + a^(2^(d-1)), f) for p = 2.
+// Returns nil if the attempt failed, and ctx.Err() if ctx is cancelled.
+func (poly *Polynom) splitEqualDegree(ctx context.Context, d int) (*Polynom, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f := poly
+	n := f.Degree()
+	a := RandomPolynom(n-1, f.P)
+	if a.Degree() == 0 {
+		return nil, nil
+	}
+
+	// lucky draw, a already shares a factor with f
+	g := GCDPolynom(a, f)
+	if g.Degree() > 0 {
+		return g, nil
+	}
+
+	var b *Polynom
+	if f.P.Cmp(big.NewInt(2)) == 0 {
+		// trace map: a + a^2 + a^4 + ... + a^(2^(d-1))
+		b = a.Copy()
+		t := a.Copy()
+		for i := 1; i < d; i++ {
+			t = t.PowMod(big.NewInt(2), f)
+			b = b.Add(t)
+		}
+	} else {
+		e := new(big.Int).Exp(f.P, big.NewInt(int64(d)), nil) // p^d
+		e.Sub(e, big.NewInt(1))                               // p^d - 1
+		e.Rsh(e, 1)                                           // (p^d - 1) / 2
+		var err error
+		b, err = a.PowModContext(ctx, e, f)
+		if err != nil {
+			return nil, err
+		}
+		b = b.Sub(NewPolynom([]*big.Int{big.NewInt(1)}, f.P))
+	}
+
+	g = GCDPolynom(b, f)
+	if g.Degree() > 0 && g.Degree() < n {
+		return g, nil
+	}
+	return nil, nil
+}
+
+// RandomPolynom returns a polynom of degree at most deg with uniformly random coefficients in F_p.
+func RandomPolynom(deg int, p *big.Int) *Polynom {
+	coeffs := make([]*big.Int, deg+1)
+	for i := range coeffs {
+		c, err := rand.Int(rand.Reader, p)
+		if err != nil {
+			panic(err)
+		}
+		coeffs[i] = c
+	}
+	return NewPolynom(coeffs, p)
+}
+
+// sortFactors sorts the factors by degree, then by coefficients (from the highest degree),
+// to have a deterministic factorization output.
+func sortFactors(factors []Factor) {
+	sort.Slice(factors, func(i, j int) bool {
+		return comparePolynoms(factors[i].Poly, factors[j].Poly) < 0
+	})
+}
+
+// comparePolynoms compares by degree, then coefficients from the highest degree
+func comparePolynoms(a, b *Polynom) int {
+	if a.Degree() != b.Degree() {
+		if a.Degree() < b.Degree() {
+			return -1
+		}
+		return 1
+	}
+	for i := a.Degree(); i >= 0; i-- {
+		if c := a.Coeff(i).Cmp(b.Coeff(i)); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// Roots returns the distinct roots in F_p of the (non null) polynom, in increasing order:
+// gcd(f, x^p - x) is the product of the x - r, split with EqualDegreeFactorization.
+func (poly *Polynom) Roots() []*big.Int {
+	f := poly.Monic()
+	f.trimTrailingZeros()
+	if f.Degree() == 0 {
+		return nil
+	}
+	x := NewPolynom([]*big.Int{big.NewInt(0), big.NewInt(1)}, poly.P)
+	xp := NewModulus(f).PowMod(x, poly.P)
+	g := GCDPolynom(f, xp.Sub(x))
+	if g.Degree() == 0 {
+		return nil
+	}
+
+	var roots []*big.Int
+	for _, factor := range g.EqualDegreeFactorization(1) {
+		// monic x + c, root -c
+		r := new(big.Int).Neg(factor.Coeff(0))
+		roots = append(roots, r.Mod(r, poly.P))
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		return roots[i].Cmp(roots[j]) < 0
+	})
+	return roots
+}
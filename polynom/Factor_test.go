@@ -0,0 +1,169 @@
+package polynom
+
+import (
+	"math/big"
+	"testing"
+)
+
+// secp256k1 field prime, = 3 mod 4
+var secp256k1P, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+
+func poly(p int64, coeffs ...int64) *Polynom {
+	c := make([]*big.Int, len(coeffs))
+	for i, v := range coeffs {
+		c[i] = big.NewInt(v)
+	}
+	return NewPolynom(c, big.NewInt(p))
+}
+
+func pow(f *Polynom, e int) *Polynom {
+	res := NewPolynom([]*big.Int{big.NewInt(1)}, f.P)
+	for i := 0; i < e; i++ {
+		res = res.Mul(f)
+	}
+	return res
+}
+
+// expand Returns lc * prod(f_i ^ e_i)
+func expand(factors []Factor, lc *big.Int) *Polynom {
+	res := NewPolynom([]*big.Int{lc}, factors[0].Poly.P)
+	for _, f := range factors {
+		res = res.Mul(pow(f.Poly, f.Multiplicity))
+	}
+	return res
+}
+
+func checkFactorization(t *testing.T, f *Polynom, want []Factor) {
+	t.Helper()
+	got := f.Factor()
+	if len(got) != len(want) {
+		t.Fatalf("Factor(%s): %d factors, want %d", f, len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Poly.Equals(want[i].Poly) || got[i].Multiplicity != want[i].Multiplicity {
+			t.Errorf("Factor(%s)[%d] = (%s)^%d, want (%s)^%d",
+				f, i, got[i].Poly, got[i].Multiplicity, want[i].Poly, want[i].Multiplicity)
+		}
+		if !got[i].Poly.IsIrreducible() {
+			t.Errorf("factor %s is not irreducible", got[i].Poly)
+		}
+	}
+	if !expand(got, f.LeadingCoeff()).Equals(f) {
+		t.Errorf("the factors of %s do not multiply back to it", f)
+	}
+}
+
+func TestFactorKnownProducts(t *testing.T) {
+	// mod 7: x + 1, x² + 1 (-1 is not a square, 7 = 3 mod 4), x³ + x + 1 (no root)
+	linear, quadratic, cubic := poly(7, 1, 1), poly(7, 1, 0, 1), poly(7, 1, 1, 0, 1)
+	f := pow(linear, 3).Mul(pow(quadratic, 2)).Mul(cubic).Scale(big.NewInt(3))
+	checkFactorization(t, f, []Factor{{linear, 3}, {quadratic, 2}, {cubic, 1}})
+
+	// mod 5, with a p-th power: (x + 2)^5 (x² + 2), 3 not being a square
+	linear, quadratic = poly(5, 2, 1), poly(5, 2, 0, 1)
+	f = pow(linear, 5).Mul(quadratic)
+	checkFactorization(t, f, []Factor{{linear, 5}, {quadratic, 1}})
+}
+
+func TestFactor256Bits(t *testing.T) {
+	p := secp256k1P
+	x5 := NewPolynom([]*big.Int{big.NewInt(-5), big.NewInt(1)}, p)
+	x7 := NewPolynom([]*big.Int{big.NewInt(-7), big.NewInt(1)}, p)
+	x2 := NewPolynom([]*big.Int{big.NewInt(1), big.NewInt(0), big.NewInt(1)}, p) // x² + 1, p = 3 mod 4
+	cubic := RandomIrreducible(3, p)
+
+	f := x5.Mul(pow(x7, 2)).Mul(x2).Mul(cubic)
+	want := []Factor{{x5, 1}, {x7, 2}, {x2, 1}, {cubic, 1}}
+	sortFactors(want)
+	checkFactorization(t, f, want)
+}
+
+func TestFactorRemultiply(t *testing.T) {
+	p := big.NewInt(101)
+	for i := 0; i < 20; i++ {
+		f := RandomPolynom(12, p)
+		if f.Degree() == 0 {
+			continue
+		}
+		factors := f.Factor()
+		if !expand(factors, f.LeadingCoeff()).Equals(f) {
+			t.Errorf("the factors of %s do not multiply back to it", f)
+		}
+	}
+}
+
+func TestSquareFreeFactorization(t *testing.T) {
+	// (x + 1)^3 (x + 2)^3 (x² + 1), mod 7: f_1 = x² + 1, f_3 = (x + 1)(x + 2)
+	f := pow(poly(7, 1, 1).Mul(poly(7, 2, 1)), 3).Mul(poly(7, 1, 0, 1))
+	sf := f.SquareFreeFactorization()
+	want := map[int]*Polynom{1: poly(7, 1, 0, 1), 3: poly(7, 2, 3, 1)}
+	if len(sf) != len(want) {
+		t.Fatalf("SquareFreeFactorization: %d factors, want %d", len(sf), len(want))
+	}
+	for _, s := range sf {
+		if w, ok := want[s.Multiplicity]; !ok || !s.Poly.Equals(w) {
+			t.Errorf("unexpected square-free factor (%s)^%d", s.Poly, s.Multiplicity)
+		}
+	}
+}
+
+func TestDistinctAndEqualDegreeFactorization(t *testing.T) {
+	// x(x + 1)(x² + 1)(x³ + x + 1) mod 7
+	f := poly(7, 0, 1).Mul(poly(7, 1, 1)).Mul(poly(7, 1, 0, 1)).Mul(poly(7, 1, 1, 0, 1))
+	want := map[int]*Polynom{1: poly(7, 0, 1, 1), 2: poly(7, 1, 0, 1), 3: poly(7, 1, 1, 0, 1)}
+	ddf := f.DistinctDegreeFactorization()
+	if len(ddf) != len(want) {
+		t.Fatalf("DistinctDegreeFactorization: %d factors, want %d", len(ddf), len(want))
+	}
+	for _, d := range ddf {
+		if w, ok := want[d.Degree]; !ok || !d.Poly.Equals(w) {
+			t.Errorf("unexpected degree %d part %s", d.Degree, d.Poly)
+		}
+	}
+
+	// (x - 1)(x - 2)(x - 3) splits into its three linear factors
+	g := poly(7, -1, 1).Mul(poly(7, -2, 1)).Mul(poly(7, -3, 1))
+	edf := g.EqualDegreeFactorization(1)
+	if len(edf) != 3 {
+		t.Fatalf("EqualDegreeFactorization: %d factors, want 3", len(edf))
+	}
+	for _, e := range edf {
+		if e.Degree() != 1 || !DivExact(g, e).Mul(e).Equals(g) {
+			t.Errorf("%s is not a linear factor of %s", e, g)
+		}
+	}
+}
+
+func TestRoots(t *testing.T) {
+	cases := []struct {
+		f    *Polynom
+		want []int64
+	}{
+		{poly(5, 1, 0, 1), []int64{2, 3}}, // x² + 1 mod 5
+		{poly(7, 1, 0, 1), nil},           // irreducible mod 7
+		{pow(poly(7, -3, 1), 2).Mul(poly(7, -1, 1)).Mul(poly(7, 1, 0, 1)), []int64{1, 3}},                   // (x - 3)²(x - 1)(x² + 1), a double root
+		{poly(101, 0, -1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1), []int64{0, 1, 6, 14, 17, 36, 65, 84, 87, 95, 100}}, // x^11 - x, the 10-th roots of unity and 0
+	}
+	for _, c := range cases {
+		got := c.f.Roots()
+		if len(got) != len(c.want) {
+			t.Errorf("Roots(%s) = %v, want %v", c.f, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i].Int64() != c.want[i] {
+				t.Errorf("Roots(%s) = %v, want %v", c.f, got, c.want)
+				break
+			}
+		}
+	}
+
+	// 256 bits: (x - 5)(x - 7)(x² + 1), p = 3 mod 4
+	p := secp256k1P
+	x5 := NewPolynom([]*big.Int{big.NewInt(-5), big.NewInt(1)}, p)
+	x7 := NewPolynom([]*big.Int{big.NewInt(-7), big.NewInt(1)}, p)
+	x2 := NewPolynom([]*big.Int{big.NewInt(1), big.NewInt(0), big.NewInt(1)}, p)
+	if got := x5.Mul(x7).Mul(x2).Roots(); len(got) != 2 || got[0].Int64() != 5 || got[1].Int64() != 7 {
+		t.Errorf("Roots((x - 5)(x - 7)(x² + 1)) mod secp256k1 p = %v, want [5 7]", got)
+	}
+}
@@ -0,0 +1,166 @@
+package polynom
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// IsIrreducible True iff the polynom is irreducible over F_p, using Rabin's test:
+// a polynom f of degree n is irreducible iff x^(p^n) = x mod f
+// and gcd(x^(p^(n/q)) - x, f) = 1 for each prime q dividing n.
+// Constant polynoms are not irreducible, polynoms of degree 1 always are.
+func (poly *Polynom) IsIrreducible() bool {
+	f := poly.Monic()
+	f.trimTrailingZeros()
+	n := f.Degree()
+	if n == 0 {
+		return false
+	}
+	if n == 1 {
+		return true
+	}
+
+	// n/q for each prime q dividing n
+	check := make(map[int]bool)
+	for _, q := range primeFactorsInt(n) {
+		check[n/q] = true
+	}
+
+	x := NewPolynom([]*big.Int{big.NewInt(0), big.NewInt(1)}, poly.P)
+	// h = x^(p^i) mod f, obtained by composing with x^p at each step
+	mod := NewModulus(f)
+	xp := mod.PowMod(x, poly.P)
+	h := xp.Copy()
+	for i := 1; i <= n; i++ {
+		if i > 1 {
+			h = mod.Compose(h, xp)
+		}
+		if check[i] {
+			g := GCDPolynom(f, h.Sub(x))
+			if !g.isOne() {
+				return false
+			}
+		}
+	}
+	return mod.Reduce(h.Sub(x)).IsZero()
+}
+
+// RandomIrreducible returns a random monic irreducible polynom of degree deg over F_p.
+// About one monic polynom out of deg is irreducible, so only a few draws are needed.
+func RandomIrreducible(deg int, p *big.Int) *Polynom {
+	if deg < 1 {
+		panic("RandomIrreducible: degree must be >= 1")
+	}
+	for {
+		f := RandomPolynom(deg-1, p)
+		f.SetCoeff(deg, big.NewInt(1))
+		if f.IsIrreducible() {
+			return f
+		}
+	}
+}
+
+// irreducibleSearchBound the number of candidates LowestIrreducible tests before giving up
+const irreducibleSearchBound = 1 << 16
+
+// LowestIrreducible returns the first monic irreducible polynom of degree deg over F_p, by increasing weight:
+//   - the binomials x^deg + c, c = 1, 2, ..., skipped when none can be irreducible: x^n - a is irreducible only if
+//     each prime q dividing n divides p - 1, and p = 1 mod 4 when 4 divides n;
+//   - the trinomials x^deg + x^k + c, k = 1, ..., deg - 1, c = 1, 2, ...;
+//   - all the x^deg + c_(deg-1)x^(deg-1) + ... + c_0 with the lower coefficients read as the base p digits of
+//     a counter (c_0 being the least significant one), needed for small p (e.g. no trinomial of degree 8 is
+//     irreducible over F_2).
+//
+// The result is reproducible across runs, which makes it suited to build GF(p^n) moduli.
+// Fails after irreducibleSearchBound candidates.
+func LowestIrreducible(deg int, p *big.Int) (*Polynom, error) {
+	if deg < 1 {
+		panic("LowestIrreducible: degree must be >= 1")
+	}
+	if deg == 1 {
+		// x itself
+		return NewPolynom([]*big.Int{big.NewInt(0), big.NewInt(1)}, p), nil
+	}
+
+	tried := 0
+	maxC := new(big.Int).Sub(p, big.NewInt(1))
+	if maxC.Cmp(big.NewInt(irreducibleSearchBound)) > 0 {
+		maxC.SetInt64(irreducibleSearchBound)
+	}
+	// x^deg + x^k + c, k = 0 standing for the binomial x^deg + c
+	for k := 0; k < deg; k++ {
+		if k == 0 && !binomialCanBeIrreducible(deg, p) {
+			continue
+		}
+		for c := big.NewInt(1); c.Cmp(maxC) <= 0 && tried < irreducibleSearchBound; c.Add(c, big.NewInt(1)) {
+			coeffs := make([]*big.Int, deg+1)
+			for i := range coeffs {
+				coeffs[i] = big.NewInt(0)
+			}
+			coeffs[0].Set(c)
+			coeffs[k].Add(coeffs[k], big.NewInt(1))
+			coeffs[deg].SetInt64(1)
+			f := NewPolynom(coeffs, p)
+			tried++
+			if f.IsIrreducible() {
+				return f, nil
+			}
+		}
+	}
+
+	// c_0 = 0 would give a polynom divisible by x, start at 1
+	count := new(big.Int).Exp(p, big.NewInt(int64(deg)), nil) // p^deg candidates
+	for k := big.NewInt(1); k.Cmp(count) < 0 && tried < irreducibleSearchBound; k.Add(k, big.NewInt(1)) {
+		if new(big.Int).Mod(k, p).Sign() == 0 {
+			continue
+		}
+		f := polynomFromDigits(k, deg, p)
+		tried++
+		if f.IsIrreducible() {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("No irreducible polynom of degree %d over F_%s among the %d candidates tried.\n", deg, p, tried)
+}
+
+// binomialCanBeIrreducible True iff some x^n - a is irreducible over F_p: each prime q dividing n
+// divides p - 1, and p = 1 mod 4 if 4 divides n
+func binomialCanBeIrreducible(n int, p *big.Int) bool {
+	pMinus1 := new(big.Int).Sub(p, big.NewInt(1))
+	for _, q := range primeFactorsInt(n) {
+		if new(big.Int).Mod(pMinus1, big.NewInt(int64(q))).Sign() != 0 {
+			return false
+		}
+	}
+	return n%4 != 0 || new(big.Int).Mod(p, big.NewInt(4)).Int64() == 1
+}
+
+// polynomFromDigits builds x^deg + sum(d_i x^i) with d_i the i-th base p digit of k
+func polynomFromDigits(k *big.Int, deg int, p *big.Int) *Polynom {
+	coeffs := make([]*big.Int, deg+1)
+	rest := new(big.Int).Set(k)
+	for i := 0; i < deg; i++ {
+		digit := new(big.Int)
+		rest.DivMod(rest, p, digit)
+		coeffs[i] = digit
+	}
+	coeffs[deg] = big.NewInt(1)
+	return NewPolynom(coeffs, p)
+}
+
+// primeFactorsInt returns the distinct prime factors of n, by trial division
+func primeFactorsInt(n int) []int {
+	var factors []int
+	for q := 2; q*q <= n; q++ {
+		if n%q == 0 {
+			factors = append(factors, q)
+			for n%q == 0 {
+				n /= q
+			}
+		}
+	}
+	if n > 1 {
+		factors = append(factors, n)
+	}
+	return factors
+}
@@ -0,0 +1,84 @@
+package polynom
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestIsIrreducible(t *testing.T) {
+	cases := []struct {
+		f    *Polynom
+		want bool
+	}{
+		{poly(7, 1, 0, 1), true},                        // x² + 1, 7 = 3 mod 4
+		{poly(5, 1, 0, 1), false},                       // x² + 1 = (x + 2)(x + 3) mod 5
+		{poly(7, 1, 1, 0, 1), true},                     // x³ + x + 1, no root mod 7
+		{poly(2, 1, 1, 0, 0, 1), true},                  // x⁴ + x + 1 over F_2
+		{poly(2, 1, 0, 1, 0, 1), false},                 // x⁴ + x² + 1 = (x² + x + 1)² over F_2
+		{poly(7, 1, 0, 1).Mul(poly(7, 1, 0, 1)), false}, // (x² + 1)², no root but reducible
+	}
+	for _, c := range cases {
+		if got := c.f.IsIrreducible(); got != c.want {
+			t.Errorf("IsIrreducible(%s) = %t, want %t", c.f, got, c.want)
+		}
+	}
+}
+
+func TestLowestIrreducible(t *testing.T) {
+	primes := []*big.Int{big.NewInt(2), big.NewInt(3), big.NewInt(5), big.NewInt(101), secp256k1P}
+	for _, p := range primes {
+		for deg := 1; deg <= 8; deg++ {
+			if p.BitLen() > 64 && deg > 6 {
+				continue
+			}
+			f, err := LowestIrreducible(deg, p)
+			if err != nil {
+				t.Fatalf("LowestIrreducible(%d, %s): %v", deg, p, err)
+			}
+			if f.Degree() != deg || !f.LeadingCoeffIsOne() || !f.IsIrreducible() {
+				t.Errorf("LowestIrreducible(%d, %s) = %s", deg, p, f)
+			}
+		}
+	}
+}
+
+// BenchmarkLowestIrreducible the search for the largest degrees of TestLowestIrreducible:
+//
+//	go test ./polynom -run '^$' -bench LowestIrreducible
+func BenchmarkLowestIrreducible(b *testing.B) {
+	cases := []struct {
+		p   *big.Int
+		deg int
+	}{
+		{big.NewInt(101), 8},
+		{secp256k1P, 6},
+	}
+	for _, c := range cases {
+		b.Run(fmt.Sprintf("p=%dbits/deg=%d", c.p.BitLen(), c.deg), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := LowestIrreducible(c.deg, c.p); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// TestBinomialCanBeIrreducible compares the criterion with an exhaustive search of the x^n + c
+func TestBinomialCanBeIrreducible(t *testing.T) {
+	for _, p := range []int64{3, 5, 7, 13, 17, 29} {
+		P := big.NewInt(p)
+		for n := 2; n <= 8; n++ {
+			found := false
+			for c := int64(1); c < p && !found; c++ {
+				coeffs := make([]int64, n+1)
+				coeffs[0], coeffs[n] = c, 1
+				found = poly(p, coeffs...).IsIrreducible()
+			}
+			if got := binomialCanBeIrreducible(n, P); got != found {
+				t.Errorf("binomialCanBeIrreducible(%d, %d) = %t, an irreducible x^n + c exists: %t", n, p, got, found)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package polynom
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// KroneckerThreshold Number of coefficients of the smallest operand from which Mul switches
+// to Kronecker substitution: both polynoms are packed into a single big.Int each,
+// multiplied with the (asymptotically fast) big.Int multiplication, then unpacked.
+// Packing costs a few word copies only, so it beats schoolbook multiplication from about 6 coefficients,
+// for small p as well as for 256 bits p (see BenchmarkMultiplication). As big.Int multiplication is itself Karatsuba,
+// Karatsuba or Toom-3 on the coefficients, even with Kronecker products at the leaves, were measured slower
+// at every size up to 8000 coefficients, and are not implemented.
+var KroneckerThreshold = 8
+
+// mulKronecker multiplies a and b (coefficients in [0, p)) by evaluating them at x = 2^B,
+// B being large enough for any coefficient of the product over the integers
+// (i.e. > 2*log2(p) + log2(min(len(a), len(b)))) so that no slot overflows in the next one.
+// The slots are aligned on machine words to pack & unpack by copying words.
+func mulKronecker(a, b []*big.Int, p *big.Int) []*big.Int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	slotBits := 2*p.BitLen() + bits.Len(uint(n)) + 1
+	slotWords := (slotBits + bits.UintSize - 1) / bits.UintSize
+
+	A := packSlots(a, slotWords, p)
+	B := packSlots(b, slotWords, p)
+	C := new(big.Int).Mul(A, B)
+
+	return unpackSlots(C, len(a)+len(b)-1, slotWords, p)
+}
+
+// packSlots returns sum(c_i * 2^(i * slotWords * UintSize))
+func packSlots(coeffs []*big.Int, slotWords int, p *big.Int) *big.Int {
+	words := make([]big.Word, len(coeffs)*slotWords)
+	for i, c := range coeffs {
+		if c.Sign() < 0 || c.Cmp(p) >= 0 {
+			c = new(big.Int).Mod(c, p)
+		}
+		copy(words[i*slotWords:(i+1)*slotWords], c.Bits())
+	}
+	return new(big.Int).SetBits(words)
+}
+
+// unpackSlots splits v in n slots of slotWords words, each one reduced mod p
+func unpackSlots(v *big.Int, n, slotWords int, p *big.Int) []*big.Int {
+	words := v.Bits()
+	res := make([]*big.Int, n)
+	for i := range res {
+		start := i * slotWords
+		end := start + slotWords
+		if start > len(words) {
+			start = len(words)
+		}
+		if end > len(words) {
+			end = len(words)
+		}
+		slot := make([]big.Word, end-start)
+		copy(slot, words[start:end])
+		res[i] = new(big.Int).SetBits(slot)
+		res[i].Mod(res[i], p)
+	}
+	return res
+}
@@ -0,0 +1,168 @@
+package polynom
+
+import (
+	"context"
+	"math/big"
+)
+
+// Modulus A polynom h prepared for repeated reductions mod h.
+// h is normalized (monic) once, and the inverse of its reversal rev(h) = x^n h(1/x) is precomputed
+// mod x^(n-1) by Newton iteration, so that a reduction costs two multiplications instead of a long division.
+type Modulus struct {
+	h      *Polynom // monic
+	n      int      // degree of h
+	invRev *Polynom // rev(h)^-1 mod x^(n-1)
+}
+
+// NewModulus Instantiate a new modulus from the (non null) polynom h.
+func NewModulus(h *Polynom) *Modulus {
+	if h == nil || h.IsZero() {
+		panic("NewModulus: null modulus")
+	}
+	hMonic := h.Monic()
+	hMonic.trimTrailingZeros()
+	n := hMonic.Degree()
+	m := &Modulus{h: hMonic, n: n}
+	if n > 1 {
+		m.invRev = inverseSeries(hMonic.reverse(n), n-1)
+	}
+	return m
+}
+
+// Polynom returns the (monic) modulus polynom.
+func (m *Modulus) Polynom() *Polynom {
+	return m.h.Copy()
+}
+
+// Degree returns the degree of the modulus.
+func (m *Modulus) Degree() int {
+	return m.n
+}
+
+// Reduce returns a mod h.
+func (m *Modulus) Reduce(a *Polynom) *Polynom {
+	r := a.Copy()
+	r.trimTrailingZeros()
+	switch m.n {
+	case 0:
+		return NewPolynom([]*big.Int{big.NewInt(0)}, m.h.P)
+	case 1:
+		// h = x + h0, only a(-h0) remains
+		return NewPolynom([]*big.Int{evaluate(r, new(big.Int).Neg(m.h.Coeff(0)))}, m.h.P)
+	}
+
+	// a is too big for one pass (more than 2n-1 coefficients): reduce its top 2n-1 coefficients,
+	// each pass lowering the degree by n-1
+	for r.Degree() > 2*m.n-2 {
+		shift := r.Degree() - (2*m.n - 2)
+		high := NewPolynom(r.Coefficients[shift:], r.P)
+		low := NewPolynom(r.Coefficients[:shift], r.P)
+		r = m.reduceShort(high).shift(shift).Add(low)
+		r.trimTrailingZeros()
+	}
+	return m.reduceShort(r)
+}
+
+// reduceShort a mod h, for deg(a) <= 2n-2:
+// q = rev(rev(a) * invRev mod x^(deg(a)-n+1)) and a mod h = a - q*h
+func (m *Modulus) reduceShort(a *Polynom) *Polynom {
+	degA := a.Degree()
+	if degA < m.n {
+		return a
+	}
+	k := degA - m.n + 1
+	q := a.reverse(degA).Mul(m.invRev).truncate(k).reverse(k - 1)
+	r := a.Sub(q.Mul(m.h)).truncate(m.n)
+	r.trimTrailingZeros()
+	return r
+}
+
+// MulMod returns a*b mod h.
+func (m *Modulus) MulMod(a, b *Polynom) *Polynom {
+	return m.Reduce(a.Mul(b))
+}
+
+// PowMod returns a^e mod h, by square and multiply.
+func (m *Modulus) PowMod(a *Polynom, e *big.Int) *Polynom {
+	res, _ := m.PowModContext(context.Background(), a, e)
+	return res
+}
+
+// PowModContext Same as PowMod, checking for the cancellation of ctx at each bit of e.
+// Returns ctx.Err() if cancelled.
+func (m *Modulus) PowModContext(ctx context.Context, a *Polynom, e *big.Int) (*Polynom, error) {
+	res := NewPolynom([]*big.Int{big.NewInt(1)}, m.h.P)
+	if e.Sign() == 0 {
+		return res, nil
+	}
+	base := m.Reduce(a)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		res = m.MulMod(res, res)
+		if e.Bit(i) == 1 {
+			res = m.MulMod(res, base)
+		}
+	}
+	return res, nil
+}
+
+// inverseSeries returns g^-1 mod x^k (g(0) must be invertible), by Newton iteration:
+// f <- f(2 - g*f) mod x^(2i), doubling the precision at each step.
+func inverseSeries(g *Polynom, k int) *Polynom {
+	p := g.P
+	inv0 := new(big.Int).ModInverse(g.Coeff(0), p)
+	if inv0 == nil {
+		panic("inverseSeries: g(0) is not invertible")
+	}
+	f := NewPolynom([]*big.Int{inv0}, p)
+	two := NewPolynom([]*big.Int{big.NewInt(2)}, p)
+	for prec := 1; prec < k; {
+		prec *= 2
+		gf := g.truncate(prec).Mul(f).truncate(prec)
+		f = f.Mul(two.Sub(gf)).truncate(prec)
+	}
+	return f.truncate(k)
+}
+
+// reverse returns x^d * poly(1/x), i.e. the d+1 first coefficients in the reverse order
+func (poly *Polynom) reverse(d int) *Polynom {
+	coeffs := make([]*big.Int, d+1)
+	for i := 0; i <= d; i++ {
+		coeffs[i] = poly.Coeff(d - i)
+	}
+	return NewPolynom(coeffs, poly.P)
+}
+
+// truncate returns poly mod x^k
+func (poly *Polynom) truncate(k int) *Polynom {
+	if k <= 0 {
+		return NewPolynom([]*big.Int{big.NewInt(0)}, poly.P)
+	}
+	if k > len(poly.Coefficients) {
+		k = len(poly.Coefficients)
+	}
+	return NewPolynom(poly.Coefficients[:k], poly.P)
+}
+
+// shift returns poly * x^k
+func (poly *Polynom) shift(k int) *Polynom {
+	coeffs := make([]*big.Int, k+len(poly.Coefficients))
+	for i := 0; i < k; i++ {
+		coeffs[i] = big.NewInt(0)
+	}
+	copy(coeffs[k:], poly.Coefficients)
+	return NewPolynom(coeffs, poly.P)
+}
+
+// evaluate returns poly(x) mod p, with Horner's method
+func evaluate(poly *Polynom, x *big.Int) *big.Int {
+	res := big.NewInt(0)
+	for i := len(poly.Coefficients) - 1; i >= 0; i-- {
+		res.Mul(res, x)
+		res.Add(res, poly.Coefficients[i])
+		res.Mod(res, poly.P)
+	}
+	return res
+}
@@ -0,0 +1,79 @@
+package polynom
+
+import (
+	"math/big"
+	"testing"
+)
+
+// randomPolynom Returns a random polynom of degree exactly deg
+func randomPolynom(deg int, p *big.Int) *Polynom {
+	coeffs := randomCoeffs(deg+1, p)
+	for coeffs[deg].Sign() == 0 {
+		coeffs[deg] = randomCoeffs(1, p)[0]
+	}
+	return NewPolynom(coeffs, p)
+}
+
+// remainder Returns a mod h, with DivMod
+func remainder(a, h *Polynom) *Polynom {
+	_, r := a.DivMod(h)
+	return r
+}
+
+// TestModulusReduce compares the Newton iteration reduction with DivMod, for non monic h of several degrees
+// and a of degree below, equal to, and up to twice above deg(h)
+func TestModulusReduce(t *testing.T) {
+	for _, p := range []*big.Int{big.NewInt(101), secp256k1P} {
+		for _, n := range []int{0, 1, 2, 5, 17, 60} {
+			h := randomPolynom(n, p)
+			m := NewModulus(h)
+			if m.Degree() != n || !m.Polynom().LeadingCoeffIsOne() {
+				t.Fatalf("modulus of %s: degree %d, polynom %s", h, m.Degree(), m.Polynom())
+			}
+			for _, deg := range []int{0, n / 2, n, n + 1, max(2*n-1, 0), 2 * n, 2*n + 7} {
+				a := randomPolynom(deg, p)
+				if got, want := m.Reduce(a), remainder(a, h); !got.Equals(want) {
+					t.Fatalf("deg h = %d mod %s: Reduce of a degree %d polynom = %s, want %s", n, p, deg, got, want)
+				}
+			}
+			if !m.Reduce(NewPolynom([]*big.Int{big.NewInt(0)}, p)).IsZero() || !m.Reduce(h).IsZero() {
+				t.Errorf("deg h = %d mod %s: 0 or h not reduced to 0", n, p)
+			}
+		}
+	}
+}
+
+// TestModulusMulPowMod compares MulMod with Mul then DivMod, and PowMod with repeated multiplications
+func TestModulusMulPowMod(t *testing.T) {
+	for _, p := range []*big.Int{big.NewInt(101), secp256k1P} {
+		for _, n := range []int{1, 3, 24} {
+			h := randomPolynom(n, p)
+			m := NewModulus(h)
+			for i := 0; i < 5; i++ {
+				a, b := randomPolynom(n+i, p), randomPolynom(2*n, p)
+				if got, want := m.MulMod(a, b), remainder(a.Mul(b), h); !got.Equals(want) {
+					t.Fatalf("deg h = %d mod %s: MulMod = %s, want %s", n, p, got, want)
+				}
+			}
+
+			a := randomPolynom(n+2, p)
+			want := remainder(NewPolynom([]*big.Int{big.NewInt(1)}, p), h)
+			for e := int64(0); e <= 20; e++ {
+				if got := m.PowMod(a, big.NewInt(e)); !got.Equals(want) {
+					t.Fatalf("deg h = %d mod %s: PowMod(a, %d) = %s, want %s", n, p, e, got, want)
+				}
+				want = remainder(want.Mul(a), h)
+			}
+
+			// a^(e1 + e2) = a^e1·a^e2 for large exponents, and the same as Polynom.PowMod
+			e1, e2 := new(big.Int).Sub(p, big.NewInt(1)), new(big.Int).Lsh(p, 3)
+			sum := new(big.Int).Add(e1, e2)
+			if got, want := m.PowMod(a, sum), m.MulMod(m.PowMod(a, e1), m.PowMod(a, e2)); !got.Equals(want) {
+				t.Fatalf("deg h = %d mod %s: a^(e1 + e2) != a^e1·a^e2", n, p)
+			}
+			if got, want := m.PowMod(a, e2), a.PowMod(e2, h); !got.Equals(want) {
+				t.Fatalf("deg h = %d mod %s: Modulus.PowMod = %s, Polynom.PowMod = %s", n, p, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package polynom
+
+import (
+	"math/big"
+)
+
+// mulCoefficients multiplies the two coefficients lists mod p.
+// Large operands go through Kronecker substitution (see KroneckerThreshold), the small ones through
+// schoolbook multiplication, each output coefficient being then reduced mod p only once, at the end.
+func mulCoefficients(a, b []*big.Int, p *big.Int) []*big.Int {
+	if len(a) >= KroneckerThreshold && len(b) >= KroneckerThreshold {
+		return mulKronecker(a, b, p)
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	res := mulSchoolbook(a, b)
+	for _, c := range res {
+		c.Mod(c, p)
+	}
+	return res
+}
+
+// mulSchoolbook classical O(n*m) multiplication
+func mulSchoolbook(a, b []*big.Int) []*big.Int {
+	res := newIntSlice(len(a) + len(b) - 1)
+	prod := new(big.Int)
+	for i := 0; i < len(a); i++ {
+		if a[i].Sign() == 0 {
+			continue
+		}
+		for j := 0; j < len(b); j++ {
+			prod.Mul(a[i], b[j])
+			res[i+j].Add(res[i+j], prod)
+		}
+	}
+	return res
+}
+
+func newIntSlice(n int) []*big.Int {
+	res := make([]*big.Int, n)
+	for i := range res {
+		res[i] = new(big.Int)
+	}
+	return res
+}
@@ -0,0 +1,85 @@
+package polynom
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// benchmarkSizes numbers of coefficients of the operands: a few around the crossover, and those of ψ_ℓ,
+// deg ψ_ℓ = (ℓ² - 1) / 2 (12, 24, 60, 144, 264, 480, 924, 1860 for ℓ = 5, 7, 11, 17, 23, 31, 43, 61),
+// the size of the products mod ψ_ℓ in Schoof
+var benchmarkSizes = []int{4, 8, 12, 16, 24, 32, 48, 60, 144, 264, 480, 924, 1860}
+
+// multipliers the multiplication algorithms, each returning the product reduced mod p
+var multipliers = []struct {
+	name string
+	mul  func(a, b []*big.Int, p *big.Int) []*big.Int
+}{
+	{"schoolbook", func(a, b []*big.Int, p *big.Int) []*big.Int { return reduceInts(mulSchoolbook(a, b), p) }},
+	{"kronecker", mulKronecker},
+}
+
+func reduceInts(a []*big.Int, p *big.Int) []*big.Int {
+	for _, c := range a {
+		c.Mod(c, p)
+	}
+	return a
+}
+
+func randomCoeffs(n int, p *big.Int) []*big.Int {
+	coeffs := make([]*big.Int, n)
+	for i := range coeffs {
+		c, err := rand.Int(rand.Reader, p)
+		if err != nil {
+			panic(err)
+		}
+		coeffs[i] = c
+	}
+	return coeffs
+}
+
+// TestMultiplicationAlgorithms checks that all the algorithms give the schoolbook product,
+// on balanced and unbalanced operands
+func TestMultiplicationAlgorithms(t *testing.T) {
+	for _, p := range []*big.Int{big.NewInt(101), secp256k1P} {
+		for _, n := range []int{1, 2, 3, 7, 33, 100, 300} {
+			for _, m := range []int{n, 2*n + 1} {
+				a, b := randomCoeffs(m, p), randomCoeffs(n, p)
+				want := reduceInts(mulSchoolbook(a, b), p)
+				for _, mul := range multipliers {
+					got := mul.mul(a, b, p)
+					for i := range want {
+						if got[i].Cmp(want[i]) != 0 {
+							t.Fatalf("%s, %d x %d coefficients mod %s: coefficient %d differs", mul.name, m, n, p, i)
+						}
+					}
+				}
+				if got := NewPolynom(a, p).Mul(NewPolynom(b, p)); !got.Equals(NewPolynom(want, p)) {
+					t.Fatalf("Mul, %d x %d coefficients mod %s differs from schoolbook", m, n, p)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkMultiplication compares the algorithms on ψ_ℓ-sized operands, for a small, a 64 bits and a 256 bits p,
+// to place KroneckerThreshold:
+//
+//	go test ./polynom -run '^$' -bench Multiplication
+func BenchmarkMultiplication(b *testing.B) {
+	p64, _ := new(big.Int).SetString("18446744073709551629", 10)
+	for _, p := range []*big.Int{big.NewInt(101), p64, secp256k1P} {
+		for _, n := range benchmarkSizes {
+			x, y := randomCoeffs(n, p), randomCoeffs(n, p)
+			for _, mul := range multipliers {
+				b.Run(fmt.Sprintf("p=%dbits/%s/n=%d", p.BitLen(), mul.name, n), func(b *testing.B) {
+					for i := 0; i < b.N; i++ {
+						mul.mul(x, y, p)
+					}
+				})
+			}
+		}
+	}
+}
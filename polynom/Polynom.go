@@ -1,6 +1,7 @@
 package polynom
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 )
@@ -113,23 +114,12 @@ func (poly *Polynom) Sub(other *Polynom) *Polynom {
 	}
 }
 
+// Mul multiplies two polynomials mod p & return the resulting polynom.
+// Uses schoolbook multiplication for small operands, Kronecker substitution for the others (see KroneckerThreshold).
 func (poly *Polynom) Mul(other *Polynom) *Polynom {
-	degA := len(poly.Coefficients)
-	degB := len(other.Coefficients)
-	resultDegree := degA + degB - 1
-
-	resultCoeffs := make([]*big.Int, resultDegree)
-	for i := range resultCoeffs {
-		resultCoeffs[i] = big.NewInt(0)
-	}
-
-	for i := 0; i < degA; i++ {
-		for j := 0; j < degB; j++ {
-			prod := new(big.Int).Mul(poly.Coefficients[i], other.Coefficients[j])
-			prod.Mod(prod, poly.P)
-			resultCoeffs[i+j].Add(resultCoeffs[i+j], prod)
-			resultCoeffs[i+j].Mod(resultCoeffs[i+j], poly.P)
-		}
+	resultCoeffs := mulCoefficients(poly.Coefficients, other.Coefficients, poly.P)
+	if len(resultCoeffs) == 0 {
+		resultCoeffs = []*big.Int{big.NewInt(0)}
 	}
 
 	return &Polynom{
@@ -177,6 +167,11 @@ func (poly *Polynom) IsZero() bool {
 	return true
 }
 
+// Equals true iff both polynoms have the same coefficients (mod p), whatever their trailing zeros
+func (poly *Polynom) Equals(other *Polynom) bool {
+	return comparePolynoms(poly, other) == 0
+}
+
 func (poly *Polynom) NormalizeMonic() (*Polynom, *big.Int) {
 	hn := poly.Copy()
 	hn.trimTrailingZeros()
@@ -204,7 +199,7 @@ func (poly *Polynom) DivMod(h *Polynom) (*Polynom, *Polynom) {
 	R.trimTrailingZeros()
 	Q := NewPolynom([]*big.Int{big.NewInt(0)}, poly.P)
 
-	hMonic, _ := h.NormalizeMonic()
+	hMonic, inv := h.NormalizeMonic()
 	hMonic.trimTrailingZeros()
 	degH := hMonic.Degree()
 
@@ -257,33 +252,22 @@ func (poly *Polynom) DivMod(h *Polynom) (*Polynom, *Polynom) {
 		R.trimTrailingZeros()
 	}
 
+	// poly = Q * hMonic + R = (Q / lc(h)) * h + R
+	Q.Scale(inv)
 	Q.trimTrailingZeros()
 	R.trimTrailingZeros()
 	return Q, R
 }
 
+// PowMod returns poly^n mod h (h must not be null).
+// Builds a Modulus from h: when reducing many times by the same h, prefer NewModulus & Modulus.PowMod.
 func (poly *Polynom) PowMod(n *big.Int, h *Polynom) *Polynom {
-	one := NewPolynom([]*big.Int{big.NewInt(1)}, poly.P)
-	if n.Sign() == 0 {
-		return one
-	}
-	base := poly.Copy()
-	_, base = base.DivMod(h)
-	res := one
-	e := new(big.Int).Set(n)
-	zero := big.NewInt(0)
-	two := big.NewInt(2)
+	return NewModulus(h).PowMod(poly, n)
+}
 
-	for e.Cmp(zero) > 0 {
-		if new(big.Int).And(e, big.NewInt(1)).Cmp(zero) != 0 {
-			res = res.Mul(base)
-			_, res = res.DivMod(h)
-		}
-		base = base.Mul(base)
-		_, base = base.DivMod(h)
-		e.Div(e, two)
-	}
-	return res
+// PowModContext Same as PowMod, returning ctx.Err() if ctx is cancelled during the exponentiation.
+func (poly *Polynom) PowModContext(ctx context.Context, n *big.Int, h *Polynom) (*Polynom, error) {
+	return NewModulus(h).PowModContext(ctx, poly, n)
 }
 
 func PolyInvMod(f, h *Polynom) (*Polynom, bool) {
@@ -0,0 +1,171 @@
+package polynom
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// QuotientRing The ring F_p[x,y]/(y² - f(x), h(x)), e.g. with f = x³ + ax + b and h = ψ_ℓ,
+// the ring where the Frobenius endomorphism of an elliptic curve is checked on its ℓ-torsion.
+// Its elements are written a(x) + b(x)·y, with deg(a), deg(b) < deg(h).
+type QuotientRing struct {
+	f *Polynom // f mod h
+	h *Modulus
+}
+
+// RingElement An element a(x) + b(x)·y of a QuotientRing.
+type RingElement struct {
+	A    *Polynom
+	B    *Polynom
+	ring *QuotientRing
+}
+
+// NotInvertibleError Returned when inverting a ring element that is not a unit.
+// Factor is the monic gcd found with h: a non-trivial factor of h when 0 < deg(Factor) < deg(h),
+// h itself when the element vanishes everywhere.
+type NotInvertibleError struct {
+	Factor *Polynom
+}
+
+func (err *NotInvertibleError) Error() string {
+	return fmt.Sprintf("element is not invertible, gcd with the modulus: %s", err.Factor)
+}
+
+// NewQuotientRing Instantiate the ring F_p[x,y]/(y² - f(x), h(x)), h being non null.
+func NewQuotientRing(f, h *Polynom) *QuotientRing {
+	mod := NewModulus(h)
+	return &QuotientRing{f: mod.Reduce(f), h: mod}
+}
+
+// NewQuotientRingModulus Same as NewQuotientRing, reusing an already built modulus.
+func NewQuotientRingModulus(f *Polynom, h *Modulus) *QuotientRing {
+	return &QuotientRing{f: h.Reduce(f), h: h}
+}
+
+// Modulus returns the modulus h of the ring.
+func (r *QuotientRing) Modulus() *Modulus {
+	return r.h
+}
+
+// F returns f mod h (y² = f).
+func (r *QuotientRing) F() *Polynom {
+	return r.f.Copy()
+}
+
+// Element returns a(x) + b(x)·y, both reduced mod h.
+func (r *QuotientRing) Element(a, b *Polynom) *RingElement {
+	return &RingElement{A: r.h.Reduce(a), B: r.h.Reduce(b), ring: r}
+}
+
+// FromInt returns the constant k.
+func (r *QuotientRing) FromInt(k *big.Int) *RingElement {
+	return r.Element(r.constant(k), r.constant(big.NewInt(0)))
+}
+
+// Zero returns the neutral element of the addition.
+func (r *QuotientRing) Zero() *RingElement {
+	return r.FromInt(big.NewInt(0))
+}
+
+// One returns the neutral element of the multiplication.
+func (r *QuotientRing) One() *RingElement {
+	return r.FromInt(big.NewInt(1))
+}
+
+// X returns the element x.
+func (r *QuotientRing) X() *RingElement {
+	return r.Element(NewPolynom([]*big.Int{big.NewInt(0), big.NewInt(1)}, r.f.P), r.constant(big.NewInt(0)))
+}
+
+// Y returns the element y.
+func (r *QuotientRing) Y() *RingElement {
+	return r.Element(r.constant(big.NewInt(0)), r.constant(big.NewInt(1)))
+}
+
+func (r *QuotientRing) constant(k *big.Int) *Polynom {
+	return NewPolynom([]*big.Int{k}, r.f.P)
+}
+
+// Ring returns the ring the element belongs to.
+func (e *RingElement) Ring() *QuotientRing {
+	return e.ring
+}
+
+func (e *RingElement) String() string {
+	return fmt.Sprintf("(%s) + (%s)·y", e.A, e.B)
+}
+
+// Add returns e + o.
+func (e *RingElement) Add(o *RingElement) *RingElement {
+	return &RingElement{A: e.A.Add(o.A), B: e.B.Add(o.B), ring: e.ring}
+}
+
+// Sub returns e - o.
+func (e *RingElement) Sub(o *RingElement) *RingElement {
+	return &RingElement{A: e.A.Sub(o.A), B: e.B.Sub(o.B), ring: e.ring}
+}
+
+// Neg returns -e.
+func (e *RingElement) Neg() *RingElement {
+	return e.ring.Zero().Sub(e)
+}
+
+// Scale returns k·e.
+func (e *RingElement) Scale(k *big.Int) *RingElement {
+	return &RingElement{A: e.A.Copy().Scale(k), B: e.B.Copy().Scale(k), ring: e.ring}
+}
+
+// Mul returns e·o, using y² = f:
+// (a1 + b1·y)(a2 + b2·y) = a1a2 + b1b2·f + (a1b2 + a2b1)·y
+func (e *RingElement) Mul(o *RingElement) *RingElement {
+	h := e.ring.h
+	a := h.Reduce(e.A.Mul(o.A).Add(h.MulMod(e.B.Mul(o.B), e.ring.f)))
+	b := h.Reduce(e.A.Mul(o.B).Add(o.A.Mul(e.B)))
+	return &RingElement{A: a, B: b, ring: e.ring}
+}
+
+// Pow returns e^n (n >= 0), by square and multiply.
+func (e *RingElement) Pow(n *big.Int) *RingElement {
+	res := e.ring.One()
+	for i := n.BitLen() - 1; i >= 0; i-- {
+		res = res.Mul(res)
+		if n.Bit(i) == 1 {
+			res = res.Mul(e)
+		}
+	}
+	return res
+}
+
+// Inverse returns e^-1, using (a + b·y)^-1 = (a - b·y) / (a² - b²·f), the norm a² - b²·f being
+// inverted mod h with PolyExtGCD.
+// If e is not invertible, returns a *NotInvertibleError holding gcd(a² - b²·f, h).
+func (e *RingElement) Inverse() (*RingElement, error) {
+	h := e.ring.h
+	norm := h.Reduce(e.A.Mul(e.A).Sub(h.MulMod(e.B.Mul(e.B), e.ring.f)))
+	g, u, _ := PolyExtGCD(norm, h.Polynom())
+	if g.Degree() > 0 {
+		return nil, &NotInvertibleError{Factor: g}
+	}
+	// g is a non null constant, monic after PolyExtGCD normalisation: u·norm = 1 mod h
+	conj := &RingElement{A: e.A.Copy(), B: e.ring.constant(big.NewInt(0)).Sub(e.B), ring: e.ring}
+	return conj.Mul(e.ring.Element(u, e.ring.constant(big.NewInt(0)))), nil
+}
+
+// Div returns e / o, see Inverse for the error.
+func (e *RingElement) Div(o *RingElement) (*RingElement, error) {
+	inv, err := o.Inverse()
+	if err != nil {
+		return nil, err
+	}
+	return e.Mul(inv), nil
+}
+
+// IsZero true iff e = 0 in the ring.
+func (e *RingElement) IsZero() bool {
+	return e.A.IsZero() && e.B.IsZero()
+}
+
+// Equals true iff e = o in the ring.
+func (e *RingElement) Equals(o *RingElement) bool {
+	return e.A.Equals(o.A) && e.B.Equals(o.B)
+}
@@ -0,0 +1,110 @@
+package polynom
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// randomElement Returns a(x) + b(x)·y with random a, b of degree < deg(h)
+func randomElement(r *QuotientRing) *RingElement {
+	p := r.f.P
+	n := r.h.Polynom().Degree()
+	return r.Element(NewPolynom(randomCoeffs(n, p), p), NewPolynom(randomCoeffs(n, p), p))
+}
+
+func TestQuotientRingArithmetic(t *testing.T) {
+	// y² = x³ + 3x + 5 mod (x^5 + x + 1) over F_101
+	f := poly(101, 5, 3, 0, 1)
+	r := NewQuotientRing(f, poly(101, 1, 1, 0, 0, 0, 1))
+
+	if !r.Y().Mul(r.Y()).Equals(r.Element(f, poly(101, 0))) {
+		t.Errorf("y·y = %s, want f = %s", r.Y().Mul(r.Y()), f)
+	}
+	for i := 0; i < 20; i++ {
+		a, b, c := randomElement(r), randomElement(r), randomElement(r)
+		if !a.Mul(b).Equals(b.Mul(a)) {
+			t.Fatalf("ab != ba for a = %s, b = %s", a, b)
+		}
+		if !a.Mul(b.Add(c)).Equals(a.Mul(b).Add(a.Mul(c))) {
+			t.Fatalf("a(b + c) != ab + ac for a = %s, b = %s, c = %s", a, b, c)
+		}
+		if !a.Mul(b).Mul(c).Equals(a.Mul(b.Mul(c))) {
+			t.Fatalf("(ab)c != a(bc) for a = %s, b = %s, c = %s", a, b, c)
+		}
+		if !a.Sub(b).Add(b).Equals(a) || !a.Add(a.Neg()).IsZero() {
+			t.Fatalf("a - b + b != a or a - a != 0 for a = %s, b = %s", a, b)
+		}
+		if !a.Scale(big.NewInt(3)).Equals(a.Add(a).Add(a)) {
+			t.Fatalf("3a != a + a + a for a = %s", a)
+		}
+		if !a.Pow(big.NewInt(5)).Equals(a.Mul(a).Mul(a).Mul(a).Mul(a)) || !a.Pow(big.NewInt(0)).Equals(r.One()) {
+			t.Fatalf("a^5 or a^0 wrong for a = %s", a)
+		}
+	}
+}
+
+func TestQuotientRingInverse(t *testing.T) {
+	// x^5 + x + 1 = (x² + x + 1)(x³ - x² + 1) splits, y² = f mod h: most elements are units, not all
+	f := poly(101, 5, 3, 0, 1)
+	r := NewQuotientRing(f, poly(101, 1, 1, 0, 0, 0, 1))
+	units := 0
+	for i := 0; i < 50; i++ {
+		a := randomElement(r)
+		inv, err := a.Inverse()
+		if err != nil {
+			var notInvertible *NotInvertibleError
+			if !errors.As(err, &notInvertible) {
+				t.Fatalf("Inverse(%s): %v", a, err)
+			}
+			continue
+		}
+		units++
+		if !a.Mul(inv).Equals(r.One()) {
+			t.Fatalf("a·a^-1 = %s for a = %s", a.Mul(inv), a)
+		}
+		b := randomElement(r)
+		if q, err := b.Div(a); err != nil || !q.Mul(a).Equals(b) {
+			t.Fatalf("(b / a)·a != b for a = %s, b = %s: %v", a, b, err)
+		}
+	}
+	if units == 0 {
+		t.Error("no unit among 50 random elements")
+	}
+}
+
+// TestNotInvertibleError the gcd of a non unit with h gives a factor of h
+func TestNotInvertibleError(t *testing.T) {
+	g1 := poly(101, 1, 1, 1)      // x² + x + 1
+	g2 := poly(101, 1, 0, 100, 1) // x³ - x² + 1
+	h := g1.Mul(g2)
+	r := NewQuotientRing(poly(101, 5, 3, 0, 1), h)
+
+	cases := []struct {
+		name string
+		e    *RingElement
+		want *Polynom
+	}{
+		{"multiple of x² + x + 1", r.Element(g1.Copy().Scale(big.NewInt(7)), poly(101, 0)), g1},
+		{"multiple of x³ - x² + 1", r.Element(g2.Mul(poly(101, 3, 1)), poly(101, 0)), g2},
+		{"y·(x² + x + 1)", r.Element(poly(101, 0), g1), g1},
+		{"zero", r.Zero(), h},
+	}
+	for _, c := range cases {
+		_, err := c.e.Inverse()
+		var notInvertible *NotInvertibleError
+		if !errors.As(err, &notInvertible) {
+			t.Errorf("%s: Inverse error %v, want a *NotInvertibleError", c.name, err)
+			continue
+		}
+		if !notInvertible.Factor.Equals(c.want) {
+			t.Errorf("%s: factor %s, want %s", c.name, notInvertible.Factor, c.want)
+		}
+		if _, rest := h.DivMod(notInvertible.Factor); !rest.IsZero() {
+			t.Errorf("%s: factor %s does not divide h", c.name, notInvertible.Factor)
+		}
+		if _, err := r.One().Div(c.e); !errors.As(err, &notInvertible) {
+			t.Errorf("%s: Div error %v, want a *NotInvertibleError", c.name, err)
+		}
+	}
+}
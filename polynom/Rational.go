@@ -0,0 +1,89 @@
+package polynom
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Rational A rational function Num/Den of F_p(x).
+// Always kept normalized: gcd(Num, Den) = 1 and Den is monic, so that two equal
+// rational functions have the same numerator & denominator.
+type Rational struct {
+	Num *Polynom
+	Den *Polynom
+}
+
+// NewRational Instantiate the rational function num/den (den must not be null).
+func NewRational(num, den *Polynom) *Rational {
+	if den.IsZero() {
+		panic("NewRational: null denominator")
+	}
+	if num.IsZero() {
+		return &Rational{
+			Num: NewPolynom([]*big.Int{big.NewInt(0)}, num.P),
+			Den: NewPolynom([]*big.Int{big.NewInt(1)}, num.P),
+		}
+	}
+	g := GCDPolynom(num, den)
+	n := DivExact(num, g)
+	d := DivExact(den, g)
+	// make the denominator monic
+	lcInv := new(big.Int).ModInverse(d.LeadingCoeff(), d.P)
+	n.Scale(lcInv)
+	d.Scale(lcInv)
+	return &Rational{Num: n, Den: d}
+}
+
+// RationalFromPolynom returns the rational function poly/1.
+func RationalFromPolynom(poly *Polynom) *Rational {
+	return NewRational(poly, NewPolynom([]*big.Int{big.NewInt(1)}, poly.P))
+}
+
+func (r *Rational) String() string {
+	return fmt.Sprintf("(%s) / (%s)", r.Num, r.Den)
+}
+
+// Add returns r + o.
+func (r *Rational) Add(o *Rational) *Rational {
+	return NewRational(r.Num.Mul(o.Den).Add(o.Num.Mul(r.Den)), r.Den.Mul(o.Den))
+}
+
+// Sub returns r - o.
+func (r *Rational) Sub(o *Rational) *Rational {
+	return NewRational(r.Num.Mul(o.Den).Sub(o.Num.Mul(r.Den)), r.Den.Mul(o.Den))
+}
+
+// Mul returns r * o.
+func (r *Rational) Mul(o *Rational) *Rational {
+	return NewRational(r.Num.Mul(o.Num), r.Den.Mul(o.Den))
+}
+
+// MulPolynom returns r * poly.
+func (r *Rational) MulPolynom(poly *Polynom) *Rational {
+	return NewRational(r.Num.Mul(poly), r.Den)
+}
+
+// Scale returns k * r.
+func (r *Rational) Scale(k *big.Int) *Rational {
+	return NewRational(r.Num.Copy().Scale(k), r.Den)
+}
+
+// Inverse returns 1/r (r must not be null).
+func (r *Rational) Inverse() *Rational {
+	return NewRational(r.Den, r.Num)
+}
+
+// Div returns r / o (o must not be null).
+func (r *Rational) Div(o *Rational) *Rational {
+	return NewRational(r.Num.Mul(o.Den), r.Den.Mul(o.Num))
+}
+
+// IsZero true iff r = 0.
+func (r *Rational) IsZero() bool {
+	return r.Num.IsZero()
+}
+
+// Equals true iff r = o (both being normalized, compares numerators & denominators).
+func (r *Rational) Equals(o *Rational) bool {
+	return r.Num.Equals(o.Num) && r.Den.Equals(o.Den)
+}
@@ -0,0 +1,64 @@
+package polynom
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRationalNormalization(t *testing.T) {
+	// (x² - 1) / (2x - 2) = (x + 1) / 2 = 51x + 51 mod 101
+	r := NewRational(poly(101, 100, 0, 1), poly(101, 99, 2))
+	if !r.Num.Equals(poly(101, 51, 51)) || !r.Den.Equals(poly(101, 1)) {
+		t.Errorf("(x² - 1) / (2x - 2) = %s, want (51x + 51) / 1", r)
+	}
+	// same function, other representation
+	if o := NewRational(poly(101, 1, 1).Mul(poly(101, 3, 5)), poly(101, 3, 5).Scale(big.NewInt(2))); !r.Equals(o) {
+		t.Errorf("%s != %s", r, o)
+	}
+	if z := NewRational(poly(101, 0), poly(101, 4, 7)); !z.IsZero() || !z.Den.Equals(poly(101, 1)) {
+		t.Errorf("0 / (7x + 4) = %s, want 0 / 1", z)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewRational accepted a null denominator")
+		}
+	}()
+	NewRational(poly(101, 1), poly(101, 0))
+}
+
+func TestRationalArithmetic(t *testing.T) {
+	p := big.NewInt(101)
+	random := func() *Rational {
+		den := NewPolynom(randomCoeffs(4, p), p)
+		for den.IsZero() {
+			den = NewPolynom(randomCoeffs(4, p), p)
+		}
+		return NewRational(NewPolynom(randomCoeffs(5, p), p), den)
+	}
+	one := RationalFromPolynom(poly(101, 1))
+	for i := 0; i < 20; i++ {
+		a, b, c := random(), random(), random()
+		if !a.Add(b).Sub(b).Equals(a) {
+			t.Fatalf("a + b - b != a for a = %s, b = %s", a, b)
+		}
+		if !a.Mul(b.Add(c)).Equals(a.Mul(b).Add(a.Mul(c))) {
+			t.Fatalf("a(b + c) != ab + ac for a = %s, b = %s, c = %s", a, b, c)
+		}
+		if !a.Scale(big.NewInt(2)).Equals(a.Add(a)) {
+			t.Fatalf("2a != a + a for a = %s", a)
+		}
+		if !a.MulPolynom(b.Num).Equals(a.Mul(RationalFromPolynom(b.Num))) {
+			t.Fatalf("MulPolynom differs from Mul for a = %s, %s", a, b.Num)
+		}
+		if a.IsZero() || b.IsZero() {
+			continue
+		}
+		if !a.Mul(a.Inverse()).Equals(one) {
+			t.Fatalf("a·a^-1 = %s for a = %s", a.Mul(a.Inverse()), a)
+		}
+		if !a.Div(b).Mul(b).Equals(a) {
+			t.Fatalf("(a / b)·b != a for a = %s, b = %s", a, b)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package schoof
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goschoof/ec"
+	"math/big"
+	"os"
+	"slices"
+)
+
+// checkpointVersion Version of the checkpoint file format, bumped on incompatible changes
+// (2: t mod 8 replaces t mod 2)
+const checkpointVersion = 2
+
+// Residue t mod L = C, as found for one of the small primes L.
+type Residue struct {
+	L *big.Int `json:"l"`
+	C *big.Int `json:"c"`
+}
+
+// Checkpoint The state of a Schoof computation, saved after each l so that a later run can resume it:
+// the curve it belongs to, the residues t mod l already found and their CRT combination T mod M.
+// Serialized as JSON, see Save and LoadCheckpoint.
+type Checkpoint struct {
+	Version  int       `json:"version"`
+	A        *big.Int  `json:"a"`
+	B        *big.Int  `json:"b"`
+	P        *big.Int  `json:"p"`
+	T        *big.Int  `json:"t"`
+	M        *big.Int  `json:"m"`
+	Residues []Residue `json:"residues"`
+}
+
+// NewCheckpoint Instantiate the empty checkpoint of a computation on the curve (T = 0 mod M = 1).
+func NewCheckpoint(curve *ec.EllipticCurve) *Checkpoint {
+	return &Checkpoint{
+		Version: checkpointVersion,
+		A:       new(big.Int).Set(curve.GetA()),
+		B:       new(big.Int).Set(curve.GetB()),
+		P:       new(big.Int).Set(curve.GetP()),
+		T:       big.NewInt(0),
+		M:       big.NewInt(1),
+	}
+}
+
+// LoadCheckpoint Reads a checkpoint written by Save. The curve is not checked, see Check.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("checkpoint %s: %w", path, err)
+	}
+	return cp, nil
+}
+
+// Save Writes the checkpoint to path. The file is written aside then renamed,
+// so that an interrupted run never leaves a truncated checkpoint behind.
+func (cp *Checkpoint) Save(path string) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Check Returns an error if the checkpoint does not belong to the curve (a, b or p differ),
+// or if it is inconsistent: unknown version, residue out of range, l not among the ones of getSmallL(curve)
+// or not coprime with the previous ones (resuming would then combine non coprime moduli),
+// T mod M not matching the CRT combination of the residues.
+func (cp *Checkpoint) Check(curve *ec.EllipticCurve) error {
+	if cp.Version != checkpointVersion {
+		return fmt.Errorf("checkpoint: unsupported version %d", cp.Version)
+	}
+	if cp.A == nil || cp.B == nil || cp.P == nil || cp.T == nil || cp.M == nil {
+		return errors.New("checkpoint: missing field")
+	}
+	if cp.A.Cmp(curve.GetA()) != 0 || cp.B.Cmp(curve.GetB()) != 0 || cp.P.Cmp(curve.GetP()) != 0 {
+		return fmt.Errorf("checkpoint: curve mismatch, saved y² = x³ + %sx + %s mod %s, got y² = x³ + %sx + %s mod %s",
+			cp.A, cp.B, cp.P, curve.GetA(), curve.GetB(), curve.GetP())
+	}
+
+	ls := getSmallL(curve)
+	T := big.NewInt(0)
+	M := big.NewInt(1)
+	for _, r := range cp.Residues {
+		if r.L == nil || r.C == nil || r.L.Cmp(big.NewInt(2)) < 0 || r.C.Sign() < 0 || r.C.Cmp(r.L) >= 0 {
+			return fmt.Errorf("checkpoint: invalid residue t mod %s = %s", r.L, r.C)
+		}
+		if !slices.ContainsFunc(ls, func(l *big.Int) bool { return l.Cmp(r.L) == 0 }) {
+			return fmt.Errorf("checkpoint: residue t mod %s, not one of the l of the curve %v", r.L, ls)
+		}
+		if new(big.Int).GCD(nil, nil, M, r.L).Cmp(big.NewInt(1)) != 0 {
+			return fmt.Errorf("checkpoint: residue t mod %s not coprime with the previous ones (M = %s)", r.L, M)
+		}
+		T, M = crtUpdate(T, M, r.C, r.L)
+	}
+	if M.Cmp(cp.M) != 0 || T.Cmp(cp.T) != 0 {
+		return fmt.Errorf("checkpoint: CRT state (T = %s, M = %s) does not match the residues (T = %s, M = %s)",
+			cp.T, cp.M, T, M)
+	}
+	return nil
+}
+
+// Done True iff t mod l is already known.
+func (cp *Checkpoint) Done(l *big.Int) bool {
+	for _, r := range cp.Residues {
+		if r.L.Cmp(l) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// CRTState Returns T mod M.
+func (cp *Checkpoint) CRTState() *CRTState {
+	return &CRTState{T: new(big.Int).Set(cp.T), M: new(big.Int).Set(cp.M)}
+}
+
+// add Records t mod l = c and combines it into T mod M
+func (cp *Checkpoint) add(l, c *big.Int) {
+	cp.Residues = append(cp.Residues, Residue{L: new(big.Int).Set(l), C: new(big.Int).Set(c)})
+	cp.T, cp.M = crtUpdate(cp.T, cp.M, c, l)
+}
+
+// SchoofCheckpoint Same as SchoofContext, saving the checkpoint to path after each l.
+// If path already holds a checkpoint of the curve, the computation resumes from it, skipping the l already done;
+// a checkpoint of another curve (or a corrupted one) is an error, and the file is left untouched.
+func SchoofCheckpoint(ctx context.Context, curve *ec.EllipticCurve, path string) (*Result, error) {
+	cp, err := LoadCheckpoint(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		cp = NewCheckpoint(curve)
+	case err != nil:
+		return nil, err
+	default:
+		if err := cp.Check(curve); err != nil {
+			return nil, err
+		}
+	}
+	return ResumeSchoof(ctx, curve, cp, func(cp *Checkpoint) error {
+		return cp.Save(path)
+	})
+}
@@ -0,0 +1,144 @@
+package schoof
+
+import (
+	"context"
+	"encoding/json"
+	"goschoof/ec"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// checkpointCurve y² = x³ + 3x + 5 mod 1000003, of 1001205 points (t = -1201), and a checkpoint of its first two l
+func checkpointCurve(t *testing.T) (*ec.EllipticCurve, *Checkpoint) {
+	t.Helper()
+	curve, err := ec.NewEllipticCurve(big.NewInt(3), big.NewInt(5), big.NewInt(1000003))
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace := big.NewInt(-1201)
+	cp := NewCheckpoint(curve)
+	for _, l := range getSmallL(curve)[:2] {
+		cp.add(l, new(big.Int).Mod(trace, l))
+	}
+	return curve, cp
+}
+
+// writeCheckpoint Writes cp, even an invalid one, to a file of a temporary directory
+func writeCheckpoint(t *testing.T, cp *Checkpoint) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	if err := cp.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	curve, cp := checkpointCurve(t)
+	path := writeCheckpoint(t, cp)
+
+	loaded, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.Check(curve); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := json.Marshal(cp)
+	got, _ := json.Marshal(loaded)
+	if string(got) != string(want) {
+		t.Errorf("loaded %s, saved %s", got, want)
+	}
+	if !loaded.Done(cp.Residues[0].L) || loaded.Done(big.NewInt(13)) {
+		t.Errorf("Done disagrees with the residues %v", loaded.Residues)
+	}
+}
+
+// TestSchoofCheckpointResume resumes from a checkpoint of the first two l: only the other ones are computed,
+// and the file ends up holding all of them
+func TestSchoofCheckpointResume(t *testing.T) {
+	curve, cp := checkpointCurve(t)
+	path := writeCheckpoint(t, cp)
+
+	res, err := SchoofCheckpoint(context.Background(), curve, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.N.Int64() != 1001205 {
+		t.Errorf("resumed count N = %s, want 1001205", res.N)
+	}
+	ls := getSmallL(curve)
+	if len(res.Timings) != len(ls)-2 {
+		t.Errorf("%d l computed on resuming, want %d", len(res.Timings), len(ls)-2)
+	}
+	for i, r := range cp.Residues {
+		if res.Residues[i].L.Cmp(r.L) != 0 || res.Residues[i].C.Cmp(r.C) != 0 {
+			t.Errorf("residue %d: t mod %s = %s, saved t mod %s = %s", i, res.Residues[i].L, res.Residues[i].C, r.L, r.C)
+		}
+	}
+
+	final, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := final.Check(curve); err != nil {
+		t.Fatal(err)
+	}
+	if len(final.Residues) != len(ls) || final.M.Cmp(res.CRT.M) != 0 {
+		t.Errorf("final checkpoint: %d residues, M = %s, want %d, M = %s", len(final.Residues), final.M, len(ls), res.CRT.M)
+	}
+
+	// a complete checkpoint only needs the final verification
+	res, err = SchoofCheckpoint(context.Background(), curve, path)
+	if err != nil || res.N.Int64() != 1001205 || len(res.Timings) != 0 {
+		t.Errorf("complete checkpoint: N = %v, %d l computed, %v", res.N, len(res.Timings), err)
+	}
+}
+
+func TestSchoofCheckpointCorrupt(t *testing.T) {
+	curve, _ := checkpointCurve(t)
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	corrupt := []byte(`{"version": 2, "a": 3, "residues": [`)
+	if err := os.WriteFile(path, corrupt, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SchoofCheckpoint(context.Background(), curve, path); err == nil {
+		t.Error("corrupt checkpoint accepted")
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != string(corrupt) {
+		t.Errorf("corrupt checkpoint overwritten: %q, %v", data, err)
+	}
+}
+
+// TestCheckpointCheck Check must reject what would make the resumed computation wrong, or crtUpdate panic
+func TestCheckpointCheck(t *testing.T) {
+	cases := []struct {
+		name   string
+		modify func(cp *Checkpoint)
+	}{
+		{"version", func(cp *Checkpoint) { cp.Version = checkpointVersion - 1 }},
+		{"curve", func(cp *Checkpoint) { cp.B = big.NewInt(6) }},
+		{"missing field", func(cp *Checkpoint) { cp.T = nil }},
+		{"residue out of range", func(cp *Checkpoint) { cp.Residues[1].C = new(big.Int).Set(cp.Residues[1].L) }},
+		{"T mismatch", func(cp *Checkpoint) { cp.T.Add(cp.T, big.NewInt(1)) }},
+		// t mod 4 is not t mod 8: resuming would combine 4 with 8
+		{"l not of the curve", func(cp *Checkpoint) {
+			cp.Residues = []Residue{{L: big.NewInt(4), C: big.NewInt(3)}}
+			cp.T, cp.M = big.NewInt(3), big.NewInt(4)
+		}},
+		{"l given twice", func(cp *Checkpoint) { cp.Residues = append(cp.Residues, cp.Residues[1]) }},
+	}
+	for _, c := range cases {
+		curve, cp := checkpointCurve(t)
+		c.modify(cp)
+		if err := cp.Check(curve); err == nil {
+			t.Errorf("%s: checkpoint accepted", c.name)
+		}
+		// and so refused by SchoofCheckpoint, without panicking
+		if _, err := SchoofCheckpoint(context.Background(), curve, writeCheckpoint(t, cp)); err == nil {
+			t.Errorf("%s: SchoofCheckpoint accepted the checkpoint", c.name)
+		}
+	}
+}
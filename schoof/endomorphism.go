@@ -0,0 +1,204 @@
+package schoof
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goschoof/ec"
+	"goschoof/polynom"
+	"math/big"
+	"time"
+)
+
+// ringPoint Affine point of the curve with coordinates in F_p[x,y]/(y² - x³ - ax - b, h).
+// The neutral element (omega) is nil, as for ec.Point.
+type ringPoint struct {
+	x *polynom.RingElement
+	y *polynom.RingElement
+}
+
+func (pt *ringPoint) Equals(other *ringPoint) bool {
+	if pt == nil || other == nil {
+		return pt == nil && other == nil
+	}
+	return pt.x.Equals(other.x) && pt.y.Equals(other.y)
+}
+
+// weierstrassPolynom x³ + ax + b
+func weierstrassPolynom(curve *ec.EllipticCurve) *polynom.Polynom {
+	return polynom.NewPolynom([]*big.Int{
+		curve.GetB(), curve.GetA(), big.NewInt(0), big.NewInt(1),
+	}, curve.GetP())
+}
+
+// ringAdd Returns p + q, with the same chord & tangent formulas as ec.SumPointsOnCurve.
+// When a slope denominator is not invertible (it vanishes for some of the points only),
+// returns the *polynom.NotInvertibleError holding the factor of h found.
+func ringAdd(curve *ec.EllipticCurve, p, q *ringPoint) (*ringPoint, error) {
+	if p == nil {
+		return q, nil
+	}
+	if q == nil {
+		return p, nil
+	}
+
+	var slope *polynom.RingElement
+	if p.x.Equals(q.x) {
+		if p.y.Add(q.y).IsZero() {
+			// p = -q
+			return nil, nil
+		}
+		if !p.y.Equals(q.y) {
+			// p = q for some of the points, p = -q for the others: find where
+			if _, err := p.y.Add(q.y).Inverse(); err != nil {
+				return nil, err
+			}
+		}
+		// (3x² + a) / 2y
+		ring := p.x.Ring()
+		num := p.x.Mul(p.x).Scale(big.NewInt(3)).Add(ring.FromInt(curve.GetA()))
+		s, err := num.Div(p.y.Scale(big.NewInt(2)))
+		if err != nil {
+			return nil, err
+		}
+		slope = s
+	} else {
+		// (q.y - p.y) / (q.x - p.x)
+		s, err := q.y.Sub(p.y).Div(q.x.Sub(p.x))
+		if err != nil {
+			return nil, err
+		}
+		slope = s
+	}
+
+	rx := slope.Mul(slope).Sub(p.x).Sub(q.x) // slope² - p.x - q.x
+	ry := slope.Mul(p.x.Sub(rx)).Sub(p.y)    // slope * (p.x - r.x) - p.y
+	return &ringPoint{x: rx, y: ry}, nil
+}
+
+// ringMul Returns [n]p, with double & add.
+func ringMul(curve *ec.EllipticCurve, p *ringPoint, n *big.Int) (*ringPoint, error) {
+	var res *ringPoint
+	for i := n.BitLen() - 1; i >= 0; i-- {
+		tmp, err := ringAdd(curve, res, res)
+		if err != nil {
+			return nil, err
+		}
+		res = tmp
+		if n.Bit(i) == 1 {
+			tmp, err = ringAdd(curve, res, p)
+			if err != nil {
+				return nil, err
+			}
+			res = tmp
+		}
+	}
+	return res, nil
+}
+
+// frobeniusImages Returns π(P) = (x^p, y^p) and π²(P) = (x^(p²), y^(p²)) for the generic point P = (x, y),
+// with y^p = (x³ + ax + b)^((p-1)/2)·y and y^(p²) = Yp(x^p)·Yp(x)·y, Yp being (x³ + ax + b)^((p-1)/2).
+// x^(p²) is obtained by composition of x^p with itself.
+// Returns ctx.Err() if ctx is cancelled during the exponentiations.
+func frobeniusImages(ctx context.Context, curve *ec.EllipticCurve, ring *polynom.QuotientRing) (*ringPoint, *ringPoint, error) {
+	h := ring.Modulus()
+	p := curve.GetP()
+	zero := polynom.NewPolynom([]*big.Int{big.NewInt(0)}, p)
+	x := polynom.NewPolynom([]*big.Int{big.NewInt(0), big.NewInt(1)}, p)
+
+	halfP := new(big.Int).Rsh(p, 1) // (p-1)/2
+	Xp, err := h.PowModContext(ctx, x, p)
+	if err != nil {
+		return nil, nil, err
+	}
+	Yp, err := h.PowModContext(ctx, ring.F(), halfP)
+	if err != nil {
+		return nil, nil, err
+	}
+	Xp2 := h.Compose(Xp, Xp)
+	Yp2 := h.MulMod(h.Compose(Yp, Xp), Yp)
+
+	pi := &ringPoint{x: ring.Element(Xp, zero), y: ring.Element(zero, Yp)}
+	pi2 := &ringPoint{x: ring.Element(Xp2, zero), y: ring.Element(zero, Yp2)}
+	return pi, pi2, nil
+}
+
+// frobeniusTraceModL Finds τ = t mod l, the one such as π²(P) + [p mod l]P = [τ]π(P)
+// for the generic point P = (x, y) of F_p[x,y]/(y² - x³ - ax - b, h), h dividing ψ_l.
+// Since π(P) != O for P in E[l], only one τ in [0, l) can satisfy the equation.
+// The time spent is added to timings. Returns ctx.Err() if ctx is cancelled.
+func frobeniusTraceModL(ctx context.Context, curve *ec.EllipticCurve, l *big.Int, h *polynom.Modulus, timings *lTimings) (*big.Int, error) {
+	start := time.Now()
+	ring := polynom.NewQuotientRingModulus(weierstrassPolynom(curve), h)
+	P := &ringPoint{x: ring.X(), y: ring.Y()}
+	pi, pi2, err := frobeniusImages(ctx, curve, ring)
+	if err != nil {
+		return nil, err
+	}
+
+	qP, err := ringMul(curve, P, new(big.Int).Mod(curve.GetP(), l))
+	if err != nil {
+		return nil, err
+	}
+	target, err := ringAdd(curve, pi2, qP) // π²(P) + [p]P
+	if err != nil {
+		return nil, err
+	}
+	timings.frobenius += time.Since(start)
+	if target == nil {
+		return big.NewInt(0), nil
+	}
+
+	start = time.Now()
+	defer func() { timings.search += time.Since(start) }()
+
+	// [τ]π(P) for τ = 1..l-1
+	current := pi
+	for tau := big.NewInt(1); tau.Cmp(l) < 0; tau.Add(tau, big.NewInt(1)) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if current.Equals(target) {
+			return tau, nil
+		}
+		current, err = ringAdd(curve, current, pi)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("no t mod %s found", l)
+}
+
+// computeTmodL Returns t mod l, restarting the search modulo a smaller factor of ψ_l each time
+// an element that is not invertible reveals one (the points whose x is a root of the factor
+// are still l-torsion points, and the characteristic equation holds for them as well).
+// The time spent is added to timings. Returns ctx.Err() if ctx is cancelled.
+// The residue mod 2^twoTorsionLevel is checked against the parity of t, given by CountTorsion2PointsFromPoly:
+// N = p + 1 - t is even iff the curve has a point of order 2.
+func computeTmodL(ctx context.Context, curve *ec.EllipticCurve, l *big.Int, h *polynom.Modulus, timings *lTimings) (*big.Int, error) {
+	for {
+		c, err := frobeniusTraceModL(ctx, curve, l, h, timings)
+		var notInvertible *polynom.NotInvertibleError
+		if errors.As(err, &notInvertible) {
+			d := notInvertible.Factor
+			if d.Degree() > 0 && d.Degree() < h.Degree() {
+				if d.Degree() <= h.Degree()-d.Degree() {
+					h = polynom.NewModulus(d)
+				} else {
+					h = polynom.NewModulus(polynom.DivExact(h.Polynom(), d))
+				}
+				continue
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			panic(err)
+		}
+		if l.Bit(0) == 0 && (c.Bit(0) == 0) != (CountTorsion2PointsFromPoly(curve) > 0) {
+			panic(fmt.Sprintf("t mod %s = %s disagrees with the points of order 2", l, c))
+		}
+		return c, nil
+	}
+}
@@ -0,0 +1,105 @@
+package schoof
+
+import (
+	"context"
+	"goschoof/ec"
+	"goschoof/polynom"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+)
+
+// tModL t mod l, as computed by a worker (err set if it was interrupted)
+type tModL struct {
+	l         *big.Int
+	c         *big.Int
+	err       error
+	psiDegree int
+	psiTime   time.Duration
+	timings   *lTimings
+}
+
+// SchoofParallel Same as Schoof, computing the t mod l for the small primes l on up to workers goroutines
+// (workers < 1 meaning 1), all of them sharing the same division polynoms cache.
+// The residues are merged with crtUpdate as soon as they are found, in any order: since all the l of getSmallL
+// are used, the CRT result (and N) does not depend on this order. Result.Residues and Result.Timings are then
+// sorted by l, so that the result does not depend on the scheduling either (but for the durations).
+// When ctx is cancelled, no new l is started, the running ones are interrupted, and ctx.Err() is returned
+// along with the partial result of the l completed so far (unless all the l were already computed).
+// A supersingular curve is answered at once, see ResumeSchoof.
+func SchoofParallel(ctx context.Context, curve *ec.EllipticCurve, workers int) (*Result, error) {
+	if res := supersingularResult(curve); res != nil {
+		return res, nil
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	start := time.Now()
+	res := &Result{Method: MethodSchoofParallel}
+	ls := getSmallL(curve)
+	cp := NewCheckpoint(curve)
+
+	pr := newProgress(ctx, curve, ls, workers)
+	cache := NewPSICache(curve)
+	jobs := make(chan *big.Int)
+	results := make(chan tModL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for l := range jobs {
+				start := time.Now()
+				psi, err := torsionPolynom(ctx, curve, l, cache)
+				if err != nil {
+					results <- tModL{l: l, err: err}
+					continue
+				}
+				r := tModL{l: l, psiDegree: psi.Degree(), psiTime: time.Since(start), timings: &lTimings{}}
+				pr.lStarted(l, r.psiDegree, r.psiTime)
+				r.c, r.err = computeTmodL(ctx, curve, l, polynom.NewModulus(psi), r.timings)
+				results <- r
+			}
+		}()
+	}
+
+	// feed the workers until all the l are given or ctx is cancelled
+	go func() {
+		defer close(jobs)
+		for _, l := range ls {
+			select {
+			case jobs <- l:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	done := 0
+	for r := range results {
+		if r.err != nil {
+			continue
+		}
+		cp.add(r.l, r.c)
+		done++
+		res.addTiming(r.l, r.psiTime, r.timings)
+		pr.lDone(r.l, r.c, r.psiDegree, r.psiTime, r.timings, cp.T, cp.M)
+	}
+
+	res.CRT = cp.CRTState()
+	res.Residues = cp.Residues
+	slices.SortFunc(res.Residues, func(r, s Residue) int { return r.L.Cmp(s.L) })
+	slices.SortFunc(res.Timings, func(r, s LTiming) int { return r.L.Cmp(s.L) })
+	if done < len(ls) {
+		res.Total = time.Since(start)
+		return res, ctx.Err()
+	}
+	return res.finish(curve, start), nil
+}
@@ -0,0 +1,98 @@
+package schoof
+
+import (
+	"context"
+	"goschoof/ec"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+// TestSchoofParallel compares SchoofParallel with Schoof, the residues and the order of the timings
+// having to be the same from one run to the other whatever the scheduling
+func TestSchoofParallel(t *testing.T) {
+	cases := []struct {
+		a, b, p int64
+	}{
+		{3, 5, 1000003},
+		{3, 7, 1000003},
+		{2, 3, 97},
+		{1, 1, 2147483647},
+	}
+	for _, c := range cases {
+		curve, err := ec.NewEllipticCurve(big.NewInt(c.a), big.NewInt(c.b), big.NewInt(c.p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := Schoof(curve)
+
+		var first *Result
+		for run := 0; run < 3; run++ {
+			res, err := SchoofParallel(context.Background(), curve, 4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.N.Cmp(want.N) != 0 || res.T.Cmp(want.T) != 0 {
+				t.Errorf("SchoofParallel(y² = x³ + %dx + %d mod %d) = %s, Schoof gave %s", c.a, c.b, c.p, res.N, want.N)
+			}
+			if len(res.Residues) != len(res.Timings) {
+				t.Fatalf("y² = x³ + %dx + %d mod %d: %d residues for %d timings", c.a, c.b, c.p, len(res.Residues), len(res.Timings))
+			}
+			for i, r := range res.Residues {
+				if i > 0 && res.Residues[i-1].L.Cmp(r.L) >= 0 {
+					t.Errorf("y² = x³ + %dx + %d mod %d: residues not sorted by l: %v", c.a, c.b, c.p, res.Residues)
+				}
+				if res.Timings[i].L.Cmp(r.L) != 0 {
+					t.Errorf("y² = x³ + %dx + %d mod %d: timing %d for l = %s, residue for l = %s", c.a, c.b, c.p, i, res.Timings[i].L, r.L)
+				}
+			}
+
+			if first == nil {
+				first = res
+				continue
+			}
+			if len(res.Residues) != len(first.Residues) {
+				t.Fatalf("y² = x³ + %dx + %d mod %d: %d residues, then %d", c.a, c.b, c.p, len(first.Residues), len(res.Residues))
+			}
+			for i, r := range res.Residues {
+				if r.L.Cmp(first.Residues[i].L) != 0 || r.C.Cmp(first.Residues[i].C) != 0 {
+					t.Errorf("y² = x³ + %dx + %d mod %d: residue %d is t mod %s = %s, then t mod %s = %s",
+						c.a, c.b, c.p, i, first.Residues[i].L, first.Residues[i].C, r.L, r.C)
+				}
+			}
+		}
+	}
+}
+
+// TestPSICacheConcurrent asks a shared cache for ψ_n from several goroutines, against a cache filled by one
+func TestPSICacheConcurrent(t *testing.T) {
+	curve, err := ec.NewEllipticCurve(big.NewInt(3), big.NewInt(5), big.NewInt(1000003))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := NewPSICache(curve)
+	shared := NewPSICache(curve)
+
+	ns := []int{17, 3, 12, 23, 8, 19, 23, 5}
+	got := make([]string, len(ns))
+	var wg sync.WaitGroup
+	for i, n := range ns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i] = shared.Get(n).String()
+		}()
+	}
+	wg.Wait()
+
+	for i, n := range ns {
+		if w := want.Get(n).String(); got[i] != w {
+			t.Errorf("ψ_%d = %s, want %s", n, got[i], w)
+		}
+	}
+	for n := 0; n <= 23; n++ {
+		if !shared.Get(n).Equals(want.Get(n)) {
+			t.Errorf("ψ_%d differs once the cache is filled", n)
+		}
+	}
+}
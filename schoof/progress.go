@@ -0,0 +1,188 @@
+package schoof
+
+import (
+	"context"
+	"goschoof/ec"
+	"log/slog"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// EventKind The step of the computation an Event reports.
+type EventKind int
+
+const (
+	// EventLStarted ψ_l is known, the search of t mod l starts
+	EventLStarted EventKind = iota
+	// EventLDone t mod l was found and combined into the CRT state
+	EventLDone
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventLStarted:
+		return "l started"
+	case EventLDone:
+		return "l done"
+	}
+	return "unknown"
+}
+
+// Event Progress of a Schoof computation, given to the Observer of the context (see WithObserver).
+// The timings, C, T and M are only set for EventLDone.
+type Event struct {
+	Kind      EventKind
+	L         *big.Int
+	PsiDegree int           // degree of ψ_l (y-free, see PSI_l)
+	PsiTime   time.Duration // computing ψ_l
+	C         *big.Int      // t mod l
+
+	// FrobeniusTime is spent computing π(P), π²(P) and [p mod l]P, SearchTime looking for c such as π²(P) + [p]P = [c]π(P)
+	FrobeniusTime time.Duration
+	SearchTime    time.Duration
+
+	T      *big.Int // t mod M
+	M      *big.Int // product of the l done so far
+	Target *big.Int // M is enough once over 4*sqrt(p)
+
+	Done    int           // number of l done (in this run)
+	Total   int           // number of l to do (in this run)
+	Elapsed time.Duration // since the start of the run
+	ETA     time.Duration // rough estimate of the remaining time, see progress.eta
+}
+
+// Observer Receives the events of a Schoof computation. It is called from the computing goroutines,
+// one call at a time, and should return quickly.
+type Observer func(ev *Event)
+
+type observerKey struct{}
+
+// WithObserver Returns a copy of ctx carrying obs: the context-aware computations (SchoofContext, ResumeSchoof,
+// SchoofCheckpoint, SchoofParallel) report their progress to it. Without observer, nothing is reported.
+func WithObserver(ctx context.Context, obs Observer) context.Context {
+	return context.WithValue(ctx, observerKey{}, obs)
+}
+
+func observerFrom(ctx context.Context) Observer {
+	obs, _ := ctx.Value(observerKey{}).(Observer)
+	return obs
+}
+
+// SlogObserver Returns an Observer writing each event as a structured log record of logger
+// (the l started at debug level, the l done at info level).
+func SlogObserver(logger *slog.Logger) Observer {
+	return func(ev *Event) {
+		attrs := []slog.Attr{
+			slog.String("l", ev.L.String()),
+			slog.Int("psi_degree", ev.PsiDegree),
+			slog.Duration("psi_time", ev.PsiTime),
+		}
+		level := slog.LevelDebug
+		if ev.Kind == EventLDone {
+			level = slog.LevelInfo
+			attrs = append(attrs,
+				slog.String("c", ev.C.String()),
+				slog.Duration("frobenius_time", ev.FrobeniusTime),
+				slog.Duration("search_time", ev.SearchTime),
+				slog.String("m", ev.M.String()),
+				slog.Int("m_bits", ev.M.BitLen()),
+				slog.Int("target_bits", ev.Target.BitLen()),
+				slog.Int("done", ev.Done),
+				slog.Int("total", ev.Total),
+				slog.Duration("elapsed", ev.Elapsed),
+				slog.Duration("eta", ev.ETA),
+			)
+		}
+		logger.LogAttrs(context.Background(), level, "schoof: "+ev.Kind.String(), attrs...)
+	}
+}
+
+// lTimings Time spent finding t mod l, accumulated over the restarts of computeTmodL
+type lTimings struct {
+	frobenius time.Duration
+	search    time.Duration
+}
+
+// progress Builds and sends the events of one run
+// Safe for concurrent use (the workers of SchoofParallel report the l they start).
+type progress struct {
+	mu      sync.Mutex
+	obs     Observer
+	target  *big.Int
+	pending []*big.Int // the l not done yet
+	total   int
+	done    int
+	workers int
+	start   time.Time
+}
+
+// newProgress Returns nil if ctx has no observer: all the methods are then no-ops
+func newProgress(ctx context.Context, curve *ec.EllipticCurve, ls []*big.Int, workers int) *progress {
+	obs := observerFrom(ctx)
+	if obs == nil {
+		return nil
+	}
+	return &progress{
+		obs:     obs,
+		target:  new(big.Int).Mul(big.NewInt(4), new(big.Int).Sqrt(curve.GetP())),
+		pending: append([]*big.Int(nil), ls...),
+		total:   len(ls),
+		workers: workers,
+		start:   time.Now(),
+	}
+}
+
+func (pr *progress) lStarted(l *big.Int, psiDegree int, psiTime time.Duration) {
+	if pr == nil {
+		return
+	}
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.obs(&Event{Kind: EventLStarted, L: l, PsiDegree: psiDegree, PsiTime: psiTime})
+}
+
+func (pr *progress) lDone(l, c *big.Int, psiDegree int, psiTime time.Duration, timings *lTimings, T, M *big.Int) {
+	if pr == nil {
+		return
+	}
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	for i, pl := range pr.pending {
+		if pl.Cmp(l) == 0 {
+			pr.pending = append(pr.pending[:i], pr.pending[i+1:]...)
+			break
+		}
+	}
+	pr.done++
+	spent := psiTime + timings.frobenius + timings.search
+	pr.obs(&Event{
+		Kind:          EventLDone,
+		L:             l,
+		PsiDegree:     psiDegree,
+		PsiTime:       psiTime,
+		C:             c,
+		FrobeniusTime: timings.frobenius,
+		SearchTime:    timings.search,
+		T:             new(big.Int).Set(T),
+		M:             new(big.Int).Set(M),
+		Target:        pr.target,
+		Done:          pr.done,
+		Total:         pr.total,
+		Elapsed:       time.Since(pr.start),
+		ETA:           pr.eta(l, spent),
+	})
+}
+
+// eta Extrapolates the time of the pending l from the one l just took: with deg ψ_l ~ l²/2,
+// the cost of an l grows roughly as l⁴. Divided by the number of workers.
+func (pr *progress) eta(l *big.Int, spent time.Duration) time.Duration {
+	lf, _ := new(big.Float).SetInt(l).Float64()
+	var sum float64
+	for _, pl := range pr.pending {
+		plf, _ := new(big.Float).SetInt(pl).Float64()
+		sum += math.Pow(plf/lf, 4)
+	}
+	return time.Duration(float64(spent) * sum / float64(pr.workers))
+}
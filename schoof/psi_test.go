@@ -0,0 +1,118 @@
+package schoof
+
+import (
+	"goschoof/ec"
+	"goschoof/polynom"
+	"math/big"
+	"testing"
+)
+
+// evalAt Returns poly(x) mod p, by Horner
+func evalAt(poly *polynom.Polynom, x *big.Int) *big.Int {
+	res := big.NewInt(0)
+	for i := poly.Degree(); i >= 0; i-- {
+		res.Mul(res, x)
+		res.Add(res, poly.Coeff(i))
+		res.Mod(res, poly.P)
+	}
+	return res
+}
+
+// isKilledBy True iff [n](x, y) = O on y² = x³ + ax + b mod p (small p), by repeated additions
+func isKilledBy(a, p, x, y, n int64) bool {
+	mod := func(v int64) int64 { return (v%p + p) % p }
+	inv := func(v int64) int64 { return new(big.Int).ModInverse(big.NewInt(mod(v)), big.NewInt(p)).Int64() }
+	rx, ry, omega := x, y, false // [k](x, y)
+	for k := int64(1); k < n; k++ {
+		switch {
+		case omega:
+			rx, ry, omega = x, y, false
+		case rx == x && ry == mod(-y):
+			omega = true
+		default:
+			var l int64
+			if rx == x {
+				l = mod(mod(3*x*x+a) * inv(2*y))
+			} else {
+				l = mod(mod(ry-y) * inv(rx-x))
+			}
+			nx := mod(l*l - x - rx)
+			rx, ry = nx, mod(l*mod(x-nx)-y)
+		}
+	}
+	return omega
+}
+
+// TestPSIEvenIndex checks the y-free ψ_n (ψ_n/y for an even n) on y² = x³ + 2x + 3 mod 97: its degree and leading
+// coefficient, and that it vanishes exactly at the x of the points P, y != 0, such as [n]P = O
+func TestPSIEvenIndex(t *testing.T) {
+	p := big.NewInt(97)
+	curve, err := ec.NewEllipticCurve(big.NewInt(2), big.NewInt(3), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := NewPSICache(curve)
+	if !PSI_l(curve, big.NewInt(4), cache).Equals(BuildPolynomL4(curve)) {
+		t.Error("ψ₄ differs from BuildPolynomL4")
+	}
+
+	for n := int64(2); n <= 14; n++ {
+		psi := PSI_l(curve, big.NewInt(n), cache)
+		deg := (n*n - 1) / 2 // ψ_n = n·x^((n²-1)/2) + ... for an odd n, n·y·x^((n²-4)/2) + ... for an even n
+		if n%2 == 0 {
+			deg = (n*n - 4) / 2
+		}
+		if int64(psi.Degree()) != deg || psi.LeadingCoeff().Int64() != n {
+			t.Errorf("ψ_%d of degree %d and leading coefficient %s, want %d and %d", n, psi.Degree(), psi.LeadingCoeff(), deg, n)
+		}
+
+		for x := int64(0); x < 97; x++ {
+			y, ok := curve.ProcessYFrom(big.NewInt(x))
+			if !ok || y.Sign() == 0 {
+				continue
+			}
+			killed := isKilledBy(2, 97, x, y.Int64(), n)
+			if zero := evalAt(psi, big.NewInt(x)).Sign() == 0; zero != killed {
+				t.Errorf("ψ_%d(%d) = 0: %t, [%d](%d, %s) = O: %t", n, x, zero, n, x, y, killed)
+			}
+		}
+	}
+}
+
+// TestResolvePolynomialDivisionL3 checks that the points found from the roots of ψ3 are of order 3,
+// one for each x of a point of order 3
+func TestResolvePolynomialDivisionL3(t *testing.T) {
+	cases := []struct {
+		a, b, p int64
+		xs      int // number of x of the points of order 3
+	}{
+		{0, 9, 37, 4},  // Z/3 × Z/9: 8 points of order 3
+		{0, 5, 61, 4},  // Z/3 × Z/21
+		{0, 1, 31, 4},  // Z/6 × Z/6
+		{1, 1, 101, 1}, // Z/105: 2 points of order 3
+		{3, 5, 101, 0}, // 115 points
+	}
+	for _, c := range cases {
+		curve, err := ec.NewEllipticCurve(big.NewInt(c.a), big.NewInt(c.b), big.NewInt(c.p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		N, err := ec.CountPointsNaive(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		points := ResolvePolynomialDivisionL3(curve)
+		if len(points) != c.xs {
+			t.Errorf("y² = x³ + %dx + %d mod %d: %d points found, want %d", c.a, c.b, c.p, len(points), c.xs)
+		}
+		for _, P := range points {
+			order, err := curve.Order(P, N)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if order.Int64() != 3 {
+				t.Errorf("y² = x³ + %dx + %d mod %d: %s of order %s, want 3", c.a, c.b, c.p, P, order)
+			}
+		}
+	}
+}
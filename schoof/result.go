@@ -0,0 +1,69 @@
+package schoof
+
+import (
+	"goschoof/ec"
+	"math/big"
+	"time"
+)
+
+// The methods a Result can come from
+const (
+	MethodSchoof         = "schoof"
+	MethodSchoofParallel = "schoof-parallel"
+	MethodSupersingular  = "supersingular"
+)
+
+// LTiming Time spent on one l: computing ψ_l, then π(P), π²(P) and [p mod l]P (Frobenius), then looking for t mod l (search).
+type LTiming struct {
+	L         *big.Int
+	Psi       time.Duration
+	Frobenius time.Duration
+	Search    time.Duration
+}
+
+// Result Outcome of a point count. When the computation is interrupted, only the CRT state, the residues
+// and the timings reached so far are set, N, T and Verification being nil.
+type Result struct {
+	N            *big.Int  // number of points, omega included
+	T            *big.Int  // trace of the Frobenius, N = p + 1 - T
+	CRT          *CRTState // t mod M
+	Residues     []Residue // the t mod l, in the order they were found (including a resumed checkpoint's), by l for SchoofParallel
+	Method       string
+	Total        time.Duration // of this run
+	Timings      []LTiming     // the l computed in this run, in the order they were found, by l for SchoofParallel
+	Verification *Verification // see Verify
+}
+
+// finish Sets N and T from the CRT state, then verifies N
+func (res *Result) finish(curve *ec.EllipticCurve, start time.Time) *Result {
+	res.T = traceFromCRT(res.CRT.T, res.CRT.M)
+	res.N = new(big.Int).Add(curve.GetP(), big.NewInt(1))
+	res.N.Sub(res.N, res.T)
+	res.Total = time.Since(start)
+	res.Verification = Verify(curve, res.N, VerifyPoints)
+	return res
+}
+
+// supersingularResult Returns the result of a supersingular curve, t = 0 and N = p + 1, without Schoof's algorithm,
+// or nil if the curve is not supersingular, or below ec.SupersingularRandomMinP where Schoof's algorithm is cheap anyway.
+// Uses the randomized test of ec.EllipticCurve.IsSupersingularRandom rather than the O(p) Hasse invariant, so that
+// ordinary curves, most often rejected by a single scalar multiplication, hardly pay for it. Verify cannot catch
+// a wrong answer of the test, as the exponent of the group then divides p + 1 too: it must be exact for this p.
+func supersingularResult(curve *ec.EllipticCurve) *Result {
+	p := curve.GetP()
+	if p.Cmp(big.NewInt(ec.SupersingularRandomMinP)) < 0 || !curve.IsSupersingularRandom(ec.SupersingularDraws) {
+		return nil
+	}
+	// t = 0 mod p and |t| <= 2*sqrt(p) < p/2
+	res := &Result{Method: MethodSupersingular, CRT: &CRTState{T: big.NewInt(0), M: new(big.Int).Set(p)}}
+	res = res.finish(curve, time.Now())
+	if !res.Verification.Verified() {
+		return nil
+	}
+	return res
+}
+
+// addTiming Records the time spent on l
+func (res *Result) addTiming(l *big.Int, psiTime time.Duration, timings *lTimings) {
+	res.Timings = append(res.Timings, LTiming{L: l, Psi: psiTime, Frobenius: timings.frobenius, Search: timings.search})
+}
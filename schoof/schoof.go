@@ -1,71 +1,137 @@
 package schoof
 
 import (
+	"context"
 	"goschoof/ec"
 	"goschoof/polynom"
 	"goschoof/utils"
 	"log"
 	"math/big"
+	"slices"
+	"sync"
+	"time"
 )
 
-// PSICache Cache for PSI calculations
-// (storing result and preventing deep recursion for already done calculations).
+// PSICache Cache for PSI calculations, filled bottom-up: asking for ψ_n computes and stores
+// all of ψ_0..ψ_n (in the y-free representation, see PSI_l), each one from the smaller ones.
+// Safe for concurrent use: the missing ψ are computed outside of the lock, from a snapshot of the stored ones,
+// so that a goroutine asking for a small ψ never waits for a big one (two goroutines may then compute the same ψ_k,
+// the longest list being kept). The polynoms are never modified once stored, so the returned polynoms are shared
+// and must not be modified (Copy them first).
 type PSICache struct {
 	curve *ec.EllipticCurve
-	cache map[string]*polynom.Polynom
+	mod   *polynom.Modulus // nil, or the modulus every ψ is reduced by
+	f     *polynom.Polynom // x³ + ax + b (mod mod)
+	f2    *polynom.Polynom // f²
+	mu    sync.Mutex
+	psi   []*polynom.Polynom
 }
 
 func NewPSICache(curve *ec.EllipticCurve) *PSICache {
-	return &PSICache{
-		curve: curve,
-		cache: make(map[string]*polynom.Polynom),
-	}
+	return NewPSICacheMod(curve, nil)
 }
 
-func CountTorsion2PointsFromPoly(curve *ec.EllipticCurve) int {
-	psi2 := BuildPolynomL2(curve)
+// NewPSICacheMod Instantiate a cache where every ψ_n is reduced modulo mod (if not nil),
+// keeping the degrees bounded by deg(mod): the recurrences still hold modulo any polynom.
+func NewPSICacheMod(curve *ec.EllipticCurve, mod *polynom.Modulus) *PSICache {
+	c := &PSICache{curve: curve, mod: mod}
+	c.f = c.reduce(weierstrassPolynom(curve))
+	c.f2 = c.reduce(c.f.Mul(c.f))
 
 	p := curve.GetP()
-	if p.BitLen() > 32 { // if p is big
-		return 1
+	c.psi = []*polynom.Polynom{
+		c.reduce(polynom.NewPolynom([]*big.Int{big.NewInt(0)}, p)), // ψ₀ = 0
+		c.reduce(polynom.NewPolynom([]*big.Int{big.NewInt(1)}, p)), // ψ₁ = 1
+		c.reduce(polynom.NewPolynom([]*big.Int{big.NewInt(2)}, p)), // ψ₂ = 2y
+		c.reduce(BuildPolynomL3(curve)),
+		c.reduce(BuildPolynomL4(curve)),
+	}
+	return c
+}
+
+func (c *PSICache) reduce(poly *polynom.Polynom) *polynom.Polynom {
+	if c.mod == nil {
+		return poly
 	}
+	return c.mod.Reduce(poly)
+}
 
-	count := 0
-	zero := big.NewInt(0)
+// Get Returns ψ_n (n >= 0), computing the missing ψ_k for k <= n.
+func (c *PSICache) Get(n int) *polynom.Polynom {
+	psi, _ := c.GetContext(context.Background(), n)
+	return psi
+}
 
-	for x := big.NewInt(0); x.Cmp(p) < 0; x.Add(x, big.NewInt(1)) {
-		result := evaluatePolyAt(psi2, x)
-		if result.Cmp(zero) == 0 {
-			count++
+// GetContext Same as Get, checking for the cancellation of ctx before computing each ψ_k.
+// Returns ctx.Err() if cancelled, the ψ_k already computed staying in the cache.
+func (c *PSICache) GetContext(ctx context.Context, n int) (*polynom.Polynom, error) {
+	c.mu.Lock()
+	psi := slices.Clip(c.psi) // appending below then never writes into the shared array
+	c.mu.Unlock()
+
+	var err error
+	for k := len(psi); k <= n; k++ {
+		if err = ctx.Err(); err != nil {
+			break
 		}
+		psi = append(psi, c.next(psi, k))
 	}
 
-	return count
+	c.mu.Lock()
+	if len(psi) > len(c.psi) {
+		c.psi = psi
+	}
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return psi[n], nil
 }
 
-// evaluatePolyAt
-func evaluatePolyAt(poly *polynom.Polynom, x *big.Int) *big.Int {
-	if len(poly.Coefficients) == 0 {
-		return big.NewInt(0)
+// next Computes ψ_k from the already known psi = ψ_0..ψ_{k-1} (k >= 5)
+func (c *PSICache) next(psi []*polynom.Polynom, k int) *polynom.Polynom {
+	m := k / 2
+	if k%2 == 1 {
+		//k is odd: $ ψ_{2m+1} = ψ_{m+2} * ψ³_{m} − ψ_{m−1] * ψ³_{m+1} $
+		term1 := psi[m+2].Mul(c.cube(psi, m))   // ψ_{m+2} * ψ³_{m}
+		term2 := psi[m-1].Mul(c.cube(psi, m+1)) // ψ_{m−1] * ψ³_{m+1}
+
+		// the even terms among m, m+1 carry y⁴ = f²
+		if m%2 == 0 {
+			term1 = c.reduce(term1).Mul(c.f2)
+		} else {
+			term2 = c.reduce(term2).Mul(c.f2)
+		}
+		return c.reduce(term1.Sub(term2))
 	}
 
-	result := big.NewInt(0)
-	xPower := big.NewInt(1) // x^0 = 1
+	//k is even: $ ψ_{2m} = ψ_m / 2y * (ψ_{m+2} * ψ²_{m−1} − ψ_{m−2} * ψ²_{m+1}) $
+	// Whatever the parity of m, once divided by y (k being even),
+	// the y² of the even terms cancels the y² of the 2y denominator:
+	// ψ_{2m}/y = ψ_m * (ψ_{m+2} * ψ²_{m−1} − ψ_{m−2} * ψ²_{m+1}) / 2 in the y-free representation
+	term1 := psi[m+2].Mul(c.square(psi, m-1)) // ψ_{m+2} * ψ²_{m−1}
+	term2 := psi[m-2].Mul(c.square(psi, m+1)) // ψ_{m−2} * ψ²_{m+1}
+	diff := c.reduce(term1.Sub(term2))
 
-	for i, coeff := range poly.Coefficients {
-		if i > 0 {
-			xPower.Mul(xPower, x)
-			xPower.Mod(xPower, poly.P)
-		}
+	halfInv := new(big.Int).ModInverse(big.NewInt(2), c.curve.GetP())
+	return c.reduce(psi[m].Mul(diff)).Scale(halfInv)
+}
 
-		term := new(big.Int).Mul(coeff, xPower)
-		term.Mod(term, poly.P)
+func (c *PSICache) square(psi []*polynom.Polynom, i int) *polynom.Polynom {
+	return c.reduce(psi[i].Mul(psi[i]))
+}
 
-		result.Add(result, term)
-		result.Mod(result, poly.P)
-	}
+func (c *PSICache) cube(psi []*polynom.Polynom, i int) *polynom.Polynom {
+	return c.reduce(c.square(psi, i).Mul(psi[i]))
+}
 
-	return result
+// CountTorsion2PointsFromPoly Returns the number of points of order 2 of the curve (0, 1 or 3): the (x, 0) with x a root
+// of x³ + ax + b in F_p, counted as the degree of gcd(x^p - x, x³ + ax + b), x^p - x being the product of all the (x - c).
+func CountTorsion2PointsFromPoly(curve *ec.EllipticCurve) int {
+	psi2 := BuildPolynomL2(curve) // 4(x³ + ax + b), same roots
+	x := polynom.NewPolynom([]*big.Int{big.NewInt(0), big.NewInt(1)}, curve.GetP())
+	xp := x.PowMod(curve.GetP(), psi2)
+	return polynom.GCDPolynom(xp.Sub(x), psi2).Degree()
 }
 
 func BuildPolynomL2(curve *ec.EllipticCurve) *polynom.Polynom {
@@ -125,7 +191,7 @@ func ResolvePolynomialDivisionL3(curve *ec.EllipticCurve) []*ec.Point {
 		}
 		point, err := ec.NewPoint(x, y)
 		if err != nil {
-			log.Fatalf(err.Error())
+			log.Fatal(err)
 		}
 		points = append(points, point)
 	}
@@ -146,9 +212,8 @@ func BuildPolynomL3(curve *ec.EllipticCurve) *polynom.Polynom {
 	return poly
 }
 
+// BuildPolynomL4 ψ₄/y, with ψ₄ = 4y(x⁶ + 5ax⁴ + 20bx³ - 5a²x² - 4abx - 8b² - a³)
 func BuildPolynomL4(curve *ec.EllipticCurve) *polynom.Polynom {
-	// ψ₄ = 2(4x⁶ + 5ax⁴ + 20bx³ - 5a²x² - 4abx - 8b² - a³)
-
 	a := curve.GetA()
 	b := curve.GetB()
 	p := curve.GetP()
@@ -161,24 +226,24 @@ func BuildPolynomL4(curve *ec.EllipticCurve) *polynom.Polynom {
 
 	coeff0 := new(big.Int).Mul(big.NewInt(-8), b2)
 	coeff0.Sub(coeff0, a3)
-	coeff0.Mul(coeff0, big.NewInt(2))
+	coeff0.Mul(coeff0, big.NewInt(4))
 	coeff0.Mod(coeff0, p)
 
-	coeff1 := new(big.Int).Mul(big.NewInt(-8), ab)
+	coeff1 := new(big.Int).Mul(big.NewInt(-16), ab)
 	coeff1.Mod(coeff1, p)
 
-	coeff2 := new(big.Int).Mul(big.NewInt(-10), a2)
+	coeff2 := new(big.Int).Mul(big.NewInt(-20), a2)
 	coeff2.Mod(coeff2, p)
 
-	coeff3 := new(big.Int).Mul(big.NewInt(40), b)
+	coeff3 := new(big.Int).Mul(big.NewInt(80), b)
 	coeff3.Mod(coeff3, p)
 
-	coeff4 := new(big.Int).Mul(big.NewInt(10), a)
+	coeff4 := new(big.Int).Mul(big.NewInt(20), a)
 	coeff4.Mod(coeff4, p)
 
 	coeff5 := big.NewInt(0)
 
-	coeff6 := big.NewInt(8)
+	coeff6 := big.NewInt(4)
 
 	poly := polynom.NewPolynom([]*big.Int{
 		coeff0, coeff1, coeff2, coeff3, coeff4, coeff5, coeff6,
@@ -187,93 +252,42 @@ func BuildPolynomL4(curve *ec.EllipticCurve) *polynom.Polynom {
 	return poly
 }
 
-// PSI_l - computes psi_l
+// PSI_l - computes psi_l, in the y-free representation:
+// ψ_l itself for an odd l, ψ_l/y for an even l (ψ_l having then a factor y).
+// In the recurrences, every y² left by the even indexes is replaced by f = x³ + ax + b.
+// The result is shared with the cache and must not be modified.
 func PSI_l(curve *ec.EllipticCurve, l *big.Int, psiCache *PSICache) *polynom.Polynom {
+	psi, _ := PSI_lContext(context.Background(), curve, l, psiCache)
+	return psi
+}
+
+// PSI_lContext Same as PSI_l, returning ctx.Err() if ctx is cancelled during the computation.
+func PSI_lContext(ctx context.Context, curve *ec.EllipticCurve, l *big.Int, psiCache *PSICache) (*polynom.Polynom, error) {
 	if l.Sign() <= 0 {
 		log.Panicf("psi index (%s) <= 0: forbidden", l.String())
 	}
-
-	lStr := l.String()
-	// short-circuiting existing calculated psi values
-	if poly, ok := psiCache.cache[lStr]; ok {
-		return poly
+	if !l.IsInt64() {
+		log.Panicf("psi index (%s) too big", l.String())
 	}
-
-	var res *polynom.Polynom
-
-	switch {
-	case l.Cmp(big.NewInt(0)) == 0:
-		res = polynom.NewPolynom([]*big.Int{big.NewInt(0)}, curve.GetP())
-		psiCache.cache[lStr] = res
-		return res
-	case l.Cmp(big.NewInt(1)) == 0:
-		// if l = 1, the corresponding polynomial is only 1, with no x
-		res = polynom.NewPolynom([]*big.Int{big.NewInt(1)}, curve.GetP())
-		psiCache.cache[lStr] = res
-		return res
-	case l.Cmp(big.NewInt(2)) == 0:
-		res = BuildPolynomL2(curve)
-		psiCache.cache[lStr] = res
-		return res
-	case l.Cmp(big.NewInt(3)) == 0:
-		res = BuildPolynomL3(curve)
-		psiCache.cache[lStr] = res
-		return res
-	case l.Cmp(big.NewInt(4)) == 0:
-		res = BuildPolynomL4(curve)
-		psiCache.cache[lStr] = res
-		return res
-	}
-
-	//l is odd: $ ψ_{2m+1} = ψ_{m+2} * ψ³_{m} − ψ_{m−1] * ψ³_{m+1} $
-	// so
-	// 	   2m+1 = l
-	//<==> 2m = l - 1
-	//<==> m = (l - 1) / 2
-	if new(big.Int).Mod(l, big.NewInt(2)).Cmp(big.NewInt(0)) != 0 {
-		lMinus1 := new(big.Int).Sub(l, big.NewInt(1))
-		m := new(big.Int).Div(lMinus1, big.NewInt(2))
-		log.Printf("psi_l:: l=%v, current m=%v :: lMinus1=%v (not launching recursion yet)", l, m, lMinus1)
-
-		// to have ψ_{2m+1}, we have to calculate ψ_{m+2}, ψ³_{m}, ψ_{m-1} & ψ³_{m+1}
-		// m will be recursively a 'l' value
-		mPlus2 := new(big.Int).Add(m, big.NewInt(2))
-		log.Printf("psi_l:: l=%v, current m=%v :: mPlus2=%v (about to start recursion for PsiMp2, with mPlus2(%v) as l)", l, m, mPlus2, mPlus2)
-		PsiMp2 := PSI_l(curve, mPlus2, psiCache)
-		log.Printf("psi_l:: l=%v, current m=%v :: PsiMp2=%v (finished recursion for PsiMp2)", l, m, PsiMp2)
-
-		log.Printf("psi_l:: l=%v, current m=%v :: (about to start recursion for PsiM, with m(%v) as l)", l, m, m)
-		PsiM := PSI_l(curve, m, psiCache)
-		log.Printf("psi_l:: l=%v, current m=%v :: PsiM=%v (finished recursion for PsiM)", l, m, PsiM)
-		PsiMcubed := PsiM.Mul(PsiM).Mul(PsiM)
-
-		mMinus1 := new(big.Int).Sub(m, big.NewInt(1))
-		PsiMm1 := PSI_l(curve, mMinus1, psiCache)
-
-		mPlus1 := new(big.Int).Add(m, big.NewInt(1))
-		PsiMp1 := PSI_l(curve, mPlus1, psiCache)
-		PsiMp1cubed := PsiMp1.Mul(PsiMp1).Mul(PsiMp1)
-
-		term1 := PsiMp2.Mul(PsiMcubed)   // ψ_{m+2} * ψ³_{m}
-		term2 := PsiMm1.Mul(PsiMp1cubed) // ψ_{m−1] * ψ³_{m+1}
-		res = term1.Sub(term2)           // ψ_{m+2} * ψ³_{m} − ψ_{m−1] * ψ³_{m+1}
-	} else {
-		// forbidden even l
-		log.Panicf("psi_l with an even number is not allowed: %v", l)
+	if psiCache == nil {
+		psiCache = NewPSICache(curve)
 	}
-
-	psiCache.cache[lStr] = res
-	return res
+	return psiCache.GetContext(ctx, int(l.Int64()))
 }
 
+// twoTorsionLevel t mod 2^twoTorsionLevel is computed with the points of order 2^twoTorsionLevel, see torsionPolynom
+const twoTorsionLevel = 3
+
+// getSmallL Returns the l to use: 2^twoTorsionLevel, then the odd primes l (l != p), the first ones whose product
+// exceeds 4*sqrt(p): t being in [-2*sqrt(p), 2*sqrt(p)] (Hasse), it is then fully determined by the t mod l.
 func getSmallL(curve *ec.EllipticCurve) []*big.Int {
 	sqrtp := new(big.Int).Sqrt(curve.GetP())         // floor(sqrt(p))
 	target := new(big.Int).Mul(big.NewInt(4), sqrtp) // 4*sqrt(p)
 
-	M := big.NewInt(1)
-	var ls []*big.Int
+	M := new(big.Int).Lsh(big.NewInt(1), twoTorsionLevel)
+	ls := []*big.Int{new(big.Int).Set(M)}
 
-	for l := int64(3); ; l += 2 { // no even numbers
+	for l := int64(3); M.Cmp(target) <= 0; l += 2 { // no even numbers
 		if !utils.IsPrime(l) {
 			continue
 		}
@@ -283,13 +297,8 @@ func getSmallL(curve *ec.EllipticCurve) []*big.Int {
 			continue
 		}
 
-		tmp := new(big.Int).Mul(M, lBig)
-
-		if tmp.Cmp(target) > 0 {
-			break
-		}
-		M.Set(tmp)
-		ls = append(ls, new(big.Int).Set(lBig))
+		M.Mul(M, lBig)
+		ls = append(ls, lBig)
 	}
 	return ls
 }
@@ -315,75 +324,117 @@ func crtUpdate(T, M, c, l *big.Int) (*big.Int, *big.Int) {
 	return Tnew, Mnew
 }
 
-func Schoof(curve *ec.EllipticCurve) *big.Int {
-	ls := getSmallL(curve)
-	T := big.NewInt(0) // t mod M
-	M := big.NewInt(1) // prod of ℓ treated
-	target := new(big.Int).Mul(big.NewInt(4), new(big.Int).Sqrt(curve.GetP()))
+// CRTState The partial knowledge of the trace: t = T mod M, M being the product of the l treated so far.
+type CRTState struct {
+	T *big.Int
+	M *big.Int
+}
 
-	// l=2 ψ₂
-	count2 := CountTorsion2PointsFromPoly(curve)
-	t2 := big.NewInt(int64(count2 % 2)) // t mod 2
-	T, M = crtUpdate(T, M, t2, big.NewInt(2))
+// Schoof Counts the points of the curve with Schoof's algorithm, see SchoofContext.
+func Schoof(curve *ec.EllipticCurve) *Result {
+	res, err := SchoofContext(context.Background(), curve)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
 
-	x := polynom.NewPolynom([]*big.Int{big.NewInt(0), big.NewInt(1)}, curve.GetP())
+// CountPoints Returns the number of points of the curve, counted by Schoof, as an ec.PointCounter.
+func CountPoints(curve *ec.EllipticCurve) (*big.Int, error) {
+	return Schoof(curve).N, nil
+}
 
-	cache := NewPSICache(curve)
-	for _, l := range ls {
-		log.Printf("schoof::Schoof > treating l=%d", l)
-		h := PSI_l(curve, l, cache)
-		Xp := x.PowMod(curve.GetP(), h)
-		Xp2 := Xp.PowMod(curve.GetP(), h)
-		c := computeTmodL(curve, l, h, x, Xp, Xp2, curve.GetP(), cache)
-		T, M = crtUpdate(T, M, c, l)
-		if M.Cmp(target) > 0 {
-			break
+// SchoofContext Same as Schoof, checking for the cancellation of ctx between and during the computations of
+// the t mod l. If ctx is cancelled (or its deadline exceeded), returns ctx.Err() along with the partial result
+// (CRT state and residues reached so far); otherwise returns the verified result.
+func SchoofContext(ctx context.Context, curve *ec.EllipticCurve) (*Result, error) {
+	return ResumeSchoof(ctx, curve, NewCheckpoint(curve), nil)
+}
+
+// ResumeSchoof Same as SchoofContext, starting from the checkpoint cp (checked beforehand, see Checkpoint.Check):
+// the l already done are skipped. cp is updated after each l, then given to onResidue (if not nil),
+// e.g. to save it; an error of onResidue stops the computation.
+// A supersingular curve is answered at once (N = p + 1, method MethodSupersingular), cp being left untouched.
+func ResumeSchoof(ctx context.Context, curve *ec.EllipticCurve, cp *Checkpoint, onResidue func(*Checkpoint) error) (*Result, error) {
+	if res := supersingularResult(curve); res != nil {
+		return res, nil
+	}
+	start := time.Now()
+	res := &Result{Method: MethodSchoof}
+	snapshot := func() *Result {
+		res.CRT = cp.CRTState()
+		res.Residues = append([]Residue(nil), cp.Residues...)
+		res.Total = time.Since(start)
+		return res
+	}
+	record := func(l, c *big.Int) error {
+		cp.add(l, c)
+		if onResidue == nil {
+			return nil
 		}
+		return onResidue(cp)
 	}
 
-	N := new(big.Int).Add(curve.GetP(), big.NewInt(1))
-	N.Sub(N, T)
-	return N
-}
-
-func computeTmodL(curve *ec.EllipticCurve, l *big.Int, h *polynom.Polynom, xPoly, Xp, Xp2 *polynom.Polynom, p *big.Int, cache *PSICache) *big.Int {
-	// loop on c = 0..l-1
-	zero := big.NewInt(0)
-	one := big.NewInt(1)
-	for c := new(big.Int).Set(zero); c.Cmp(l) < 0; c.Add(c, one) {
-		// target = π^2 - c*π + p on x coordinate
-		// π(x,y) = (x^p, y^p) so π(x) = x^p = Xp
-		// π^2(x) = (x^p)^p = x^(p^2) = Xp2
-		// => target = Xp2 - c*Xp + p*1
-
-		// compute c*Xp
-		comb := Xp.Scale(c)
-		_, comb = comb.DivMod(h)
-
-		target := Xp2.Sub(comb)
-		pCoeff := new(big.Int).Mod(p, curve.GetP())
-		if target.Coeff(0).Add(target.Coeff(0), pCoeff); true {
-			target.SetCoeff(0, new(big.Int).Add(target.Coeff(0), pCoeff))
+	var ls []*big.Int
+	for _, l := range getSmallL(curve) {
+		if !cp.Done(l) {
+			ls = append(ls, l)
 		}
-		_, target = target.DivMod(h)
+	}
+	pr := newProgress(ctx, curve, ls, 1)
 
-		if target.IsZero() {
-			return new(big.Int).Set(c)
+	cache := NewPSICache(curve)
+	for _, l := range ls {
+		if err := ctx.Err(); err != nil {
+			return snapshot(), err
 		}
+		lStart := time.Now()
+		psi, err := torsionPolynom(ctx, curve, l, cache)
+		if err != nil {
+			return snapshot(), err
+		}
+		psiTime := time.Since(lStart)
+		pr.lStarted(l, psi.Degree(), psiTime)
 
-		d := polynom.GCDPolynom(h, target)
-		if d != nil && d.Degree() > 0 && d.Degree() < h.Degree() {
-			if d.Degree() <= h.Degree()-d.Degree() {
-				h = d
-			} else {
-				h = polynom.DivExact(h, d)
-			}
-			// recompute Xp, Xp2 with new h
-			Xp = xPoly.PowMod(p, h)
-			Xp2 = Xp.PowMod(p, h)
-			c.Sub(c, one)
-			continue
+		timings := &lTimings{}
+		c, err := computeTmodL(ctx, curve, l, polynom.NewModulus(psi), timings)
+		if err != nil {
+			return snapshot(), err
+		}
+		if err := record(l, c); err != nil {
+			return snapshot(), err
 		}
+		res.addTiming(l, psiTime, timings)
+		pr.lDone(l, c, psi.Degree(), psiTime, timings, cp.T, cp.M)
+	}
+
+	return snapshot().finish(curve, start), nil
+}
+
+// torsionPolynom Returns the polynom whose roots are the x of the points of order l, for l an odd prime or a power of 2:
+// ψ_l for an odd prime l, ψ_l/ψ_{l/2} for l = 2^k (k >= 2), the roots of ψ_{l/2} being the x of the points of smaller order.
+// The characteristic equation is checked on these points: for P of order l, [τ]π(P) = [t]π(P) iff τ = t mod l.
+// For l = 2 (whose points are (x, 0)), returns x³ + ax + b.
+func torsionPolynom(ctx context.Context, curve *ec.EllipticCurve, l *big.Int, cache *PSICache) (*polynom.Polynom, error) {
+	if l.Cmp(big.NewInt(2)) == 0 {
+		return weierstrassPolynom(curve), nil
+	}
+	psi, err := PSI_lContext(ctx, curve, l, cache)
+	if err != nil || l.Bit(0) == 1 {
+		return psi, err
+	}
+	half, err := PSI_lContext(ctx, curve, new(big.Int).Rsh(l, 1), cache)
+	if err != nil {
+		return nil, err
+	}
+	return polynom.DivExact(psi, half), nil
+}
+
+// traceFromCRT Returns t, the representative of T mod M in [-M/2, M/2]
+func traceFromCRT(T, M *big.Int) *big.Int {
+	t := new(big.Int).Mod(T, M)
+	if new(big.Int).Lsh(t, 1).Cmp(M) > 0 {
+		t.Sub(t, M)
 	}
-	panic("no c found!")
+	return t
 }
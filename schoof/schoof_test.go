@@ -0,0 +1,85 @@
+package schoof
+
+import (
+	"context"
+	"goschoof/ec"
+	"goschoof/polynom"
+	"math/big"
+	"testing"
+)
+
+// TestSchoofSmallPrimes compares Schoof with the naive count on every curve of the small primes, where the
+// supersingular shortcut and the choice of the l are the most fragile (about 90 s, p <= 23 only with -short)
+func TestSchoofSmallPrimes(t *testing.T) {
+	for _, p := range []int64{17, 19, 23, 29, 31, 37, 41, 43} {
+		if testing.Short() && p > 23 {
+			break
+		}
+		for a := int64(0); a < p; a++ {
+			for b := int64(0); b < p; b++ {
+				curve, err := ec.NewEllipticCurve(big.NewInt(a), big.NewInt(b), big.NewInt(p))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !curve.IsNonSingular() {
+					continue
+				}
+				want, err := ec.CountPointsNaive(curve)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if res := Schoof(curve); res.N.Cmp(want) != 0 {
+					t.Errorf("Schoof(y² = x³ + %dx + %d mod %d) = %s (%s), want %s", a, b, p, res.N, res.Method, want)
+				}
+			}
+		}
+	}
+}
+
+// TestTraceMod8 compares t mod 8, found with the points of order 8, and the number of points of order 2
+// with the naive count
+func TestTraceMod8(t *testing.T) {
+	l := big.NewInt(8)
+	for _, p := range []int64{17, 19, 23, 29, 31, 37, 41, 43, 97, 101} {
+		for a := int64(0); a < 5; a++ {
+			for b := int64(0); b < 5; b++ {
+				curve, err := ec.NewEllipticCurve(big.NewInt(a), big.NewInt(b), big.NewInt(p))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !curve.IsNonSingular() {
+					continue
+				}
+				N, err := ec.CountPointsNaive(curve)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := new(big.Int).Sub(big.NewInt(p+1), N)
+				want.Mod(want, l)
+
+				psi, err := torsionPolynom(context.Background(), curve, l, NewPSICache(curve))
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := computeTmodL(context.Background(), curve, l, polynom.NewModulus(psi), &lTimings{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Cmp(want) != 0 {
+					t.Errorf("y² = x³ + %dx + %d mod %d: t mod 8 = %s, want %s", a, b, p, got, want)
+				}
+
+				// the (x, 0) of the curve
+				order2 := 0
+				for x := int64(0); x < p; x++ {
+					if (x*x*x+a*x+b)%p == 0 {
+						order2++
+					}
+				}
+				if got := CountTorsion2PointsFromPoly(curve); got != order2 {
+					t.Errorf("y² = x³ + %dx + %d mod %d: %d points of order 2, want %d", a, b, p, got, order2)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package schoof
+
+import (
+	"goschoof/ec"
+	"goschoof/polynom"
+	"math/big"
+)
+
+// SymbolicPoint A point of the curve over F_p(x)[y]/(y² - x³ - ax - b), written (X(x), Y(x)·y):
+// since the generic point is (x, y), all its multiples have this form.
+// The neutral element (omega) is nil, as for ec.Point.
+type SymbolicPoint struct {
+	X *polynom.Rational
+	Y *polynom.Rational // the y coordinate is Y·y
+}
+
+// Equals true iff both points are the same (nil being omega).
+func (pt *SymbolicPoint) Equals(other *SymbolicPoint) bool {
+	if pt == nil || other == nil {
+		return pt == nil && other == nil
+	}
+	return pt.X.Equals(other.X) && pt.Y.Equals(other.Y)
+}
+
+// GenericPoint Returns the generic point (x, y) of the curve.
+func GenericPoint(curve *ec.EllipticCurve) *SymbolicPoint {
+	p := curve.GetP()
+	return &SymbolicPoint{
+		X: polynom.RationalFromPolynom(polynom.NewPolynom([]*big.Int{big.NewInt(0), big.NewInt(1)}, p)),
+		Y: polynom.RationalFromPolynom(polynom.NewPolynom([]*big.Int{big.NewInt(1)}, p)),
+	}
+}
+
+// SymbolicAdd Returns P + Q. With λ = L·y the slope and y² = f = x³ + ax + b:
+//   - P != ±Q: L = (Y_Q - Y_P) / (X_Q - X_P)
+//   - P = Q: L = (3X_P² + a) / (2f·Y_P)
+//
+// then X_R = f·L² - X_P - X_Q and Y_R = L(X_P - X_R) - Y_P.
+func SymbolicAdd(curve *ec.EllipticCurve, P, Q *SymbolicPoint) *SymbolicPoint {
+	if P == nil {
+		return Q
+	}
+	if Q == nil {
+		return P
+	}
+	f := weierstrassPolynom(curve)
+
+	var L *polynom.Rational
+	if P.X.Equals(Q.X) {
+		if P.Y.Add(Q.Y).IsZero() {
+			// P = -Q
+			return nil
+		}
+		a := polynom.RationalFromPolynom(polynom.NewPolynom([]*big.Int{curve.GetA()}, curve.GetP()))
+		num := P.X.Mul(P.X).Scale(big.NewInt(3)).Add(a)
+		L = num.Div(P.Y.MulPolynom(f).Scale(big.NewInt(2)))
+	} else {
+		L = Q.Y.Sub(P.Y).Div(Q.X.Sub(P.X))
+	}
+
+	X := L.Mul(L).MulPolynom(f).Sub(P.X).Sub(Q.X)
+	Y := L.Mul(P.X.Sub(X)).Sub(P.Y)
+	return &SymbolicPoint{X: X, Y: Y}
+}
+
+// SymbolicMultiply Returns [n]P (n >= 0), with double & add.
+func SymbolicMultiply(curve *ec.EllipticCurve, P *SymbolicPoint, n *big.Int) *SymbolicPoint {
+	var res *SymbolicPoint
+	for i := n.BitLen() - 1; i >= 0; i-- {
+		res = SymbolicAdd(curve, res, res)
+		if n.Bit(i) == 1 {
+			res = SymbolicAdd(curve, res, P)
+		}
+	}
+	return res
+}
+
+// MultiplicationByN Returns [n](x, y) = (φ_n/ψ_n², ω_n/ψ_n³) (n >= 1), from the division polynoms given by PSI_l,
+// with φ_n = xψ_n² - ψ_{n+1}ψ_{n-1} and 4yω_n = ψ_{n+2}ψ²_{n-1} - ψ_{n-2}ψ²_{n+1}.
+//
+// PSI_l being y-free (ψ_n for odd n, ψ_n/y for even n), the powers of y are replaced by f = x³ + ax + b:
+//   - n odd: X = x - f·ψ_{n+1}ψ_{n-1}/ψ_n², Y = (ψ_{n+2}ψ²_{n-1} - ψ_{n-2}ψ²_{n+1}) / 4ψ_n³
+//   - n even: X = x - ψ_{n+1}ψ_{n-1}/(f·ψ_n²), Y = (ψ_{n+2}ψ²_{n-1} - ψ_{n-2}ψ²_{n+1}) / 4f²ψ_n³
+func MultiplicationByN(curve *ec.EllipticCurve, n *big.Int, psiCache *PSICache) *SymbolicPoint {
+	if n.Cmp(big.NewInt(1)) == 0 {
+		return GenericPoint(curve)
+	}
+	p := curve.GetP()
+	f := weierstrassPolynom(curve)
+	x := polynom.NewPolynom([]*big.Int{big.NewInt(0), big.NewInt(1)}, p)
+
+	psi := func(k int64) *polynom.Polynom {
+		idx := new(big.Int).Add(n, big.NewInt(k))
+		if idx.Sign() == 0 {
+			return polynom.NewPolynom([]*big.Int{big.NewInt(0)}, p)
+		}
+		return PSI_l(curve, idx, psiCache)
+	}
+	psiN := psi(0)
+	psiN2 := psiN.Mul(psiN)
+	psiN3 := psiN2.Mul(psiN)
+	prodAround := psi(1).Mul(psi(-1))                                                 // ψ_{n+1}ψ_{n-1}
+	omegaNum := psi(2).Mul(psi(-1)).Mul(psi(-1)).Sub(psi(-2).Mul(psi(1)).Mul(psi(1))) // ψ_{n+2}ψ²_{n-1} - ψ_{n-2}ψ²_{n+1}
+
+	var X, Y *polynom.Rational
+	if n.Bit(0) == 1 {
+		X = polynom.RationalFromPolynom(x).Sub(polynom.NewRational(f.Mul(prodAround), psiN2))
+		Y = polynom.NewRational(omegaNum, psiN3.Scale(big.NewInt(4)))
+	} else {
+		X = polynom.RationalFromPolynom(x).Sub(polynom.NewRational(prodAround, f.Mul(psiN2)))
+		Y = polynom.NewRational(omegaNum, f.Mul(f).Mul(psiN3).Scale(big.NewInt(4)))
+	}
+	return &SymbolicPoint{X: X, Y: Y}
+}
+
+// CheckMultiplicationByN True iff [n](x, y) computed with the curve addition matches the one
+// given by the division polynoms of PSI_l.
+func CheckMultiplicationByN(curve *ec.EllipticCurve, n *big.Int, psiCache *PSICache) bool {
+	expected := SymbolicMultiply(curve, GenericPoint(curve), n)
+	return expected.Equals(MultiplicationByN(curve, n, psiCache))
+}
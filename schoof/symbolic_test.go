@@ -0,0 +1,23 @@
+package schoof
+
+import (
+	"goschoof/ec"
+	"math/big"
+	"testing"
+)
+
+// TestCheckMultiplicationByN compares [n](x, y) from the division polynoms with the one from the curve addition
+func TestCheckMultiplicationByN(t *testing.T) {
+	for _, c := range []struct{ a, b, p int64 }{{3, 5, 101}, {2, 3, 97}, {1, 1, 1000003}, {0, 7, 10007}} {
+		curve, err := ec.NewEllipticCurve(big.NewInt(c.a), big.NewInt(c.b), big.NewInt(c.p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cache := NewPSICache(curve)
+		for n := int64(1); n <= 10; n++ {
+			if !CheckMultiplicationByN(curve, big.NewInt(n), cache) {
+				t.Errorf("y² = x³ + %dx + %d mod %d: [%d](x, y) differs from the division polynoms", c.a, c.b, c.p, n)
+			}
+		}
+	}
+}
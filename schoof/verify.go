@@ -0,0 +1,78 @@
+package schoof
+
+import (
+	"goschoof/ec"
+	"goschoof/utils"
+	"math/big"
+)
+
+// VerifyPoints Number of random points checked by Verify when a count is done
+const VerifyPoints = 8
+
+// Verification Outcome of Verify for a candidate order N.
+type Verification struct {
+	Hasse       bool     // |p + 1 - N| <= 2*sqrt(p)
+	Points      int      // number of random points P checked
+	Annihilated bool     // [N]P = O for all of them
+	Exponent    *big.Int // lcm of the orders of the points, nil when N could not be factored
+	Unique      bool     // N is the only multiple of Exponent in the Hasse interval, hence the order of the curve
+}
+
+// Verified True iff N passed the checks: in the Hasse interval, and killing all the points tried.
+// It is then a multiple of the order of each of these points; see Unique for a proof that it is the order of the curve.
+func (v *Verification) Verified() bool {
+	return v.Hasse && v.Annihilated
+}
+
+// Verify Checks the candidate order N of the curve independently of the way it was computed,
+// with the curve arithmetic of ec only: N must lie in the Hasse interval and [N]P = O for random points P.
+// When N can be factored, the orders of the points are computed too: the order of the curve is a multiple of their lcm
+// lying in the Hasse interval, so if N is the only one, it is proven to be the order of the curve.
+func Verify(curve *ec.EllipticCurve, N *big.Int, points int) *Verification {
+	v := &Verification{Hasse: inHasseInterval(curve, N), Annihilated: true}
+	if N.Sign() <= 0 {
+		v.Annihilated = false
+		return v
+	}
+
+	factors, factored := utils.Factorize(N)
+	exponent := big.NewInt(1)
+	for _, P := range randomPoints(curve, points) {
+		v.Points++
+		Q, err := curve.MultiplyPointByScalar(P, new(big.Int).Set(N))
+		if err != nil || Q != nil {
+			v.Annihilated = false
+			return v
+		}
+		if factored {
+			order, _ := curve.OrderWithFactors(P, N, factors)
+			exponent.Div(new(big.Int).Mul(exponent, order), new(big.Int).GCD(nil, nil, exponent, order))
+		}
+	}
+
+	if factored {
+		v.Exponent = exponent
+		v.Unique = v.Hasse &&
+			!inHasseInterval(curve, new(big.Int).Sub(N, exponent)) &&
+			!inHasseInterval(curve, new(big.Int).Add(N, exponent))
+	}
+	return v
+}
+
+// inHasseInterval True iff |p + 1 - N| <= 2*sqrt(p), see ec.EllipticCurve.CheckHasseBound
+func inHasseInterval(curve *ec.EllipticCurve, N *big.Int) bool {
+	return curve.CheckHasseBound(N).Holds
+}
+
+// randomPoints Returns up to n random affine points of the curve (fewer on a curve with hardly any point)
+func randomPoints(curve *ec.EllipticCurve, n int) []*ec.Point {
+	var points []*ec.Point
+	for len(points) < n {
+		P := curve.RandomPoint()
+		if P == nil {
+			break
+		}
+		points = append(points, P)
+	}
+	return points
+}
@@ -0,0 +1,118 @@
+package schoof
+
+import (
+	"fmt"
+	"goschoof/ec"
+	"math/big"
+)
+
+// Zeta The zeta function of an elliptic curve over F_p, Z(T) = L(T) / ((1 - T)(1 - pT)), with the L-polynomial
+// L(T) = 1 - tT + pT², t being the trace of the Frobenius: its characteristic polynomial is X² - tX + p = (X - α)(X - β),
+// and L(T) = (1 - αT)(1 - βT). Everything over the extensions F_(p^k) follows from p and t alone.
+type Zeta struct {
+	P *big.Int
+	T *big.Int
+}
+
+// NewZeta Instantiate the zeta function of a curve over F_p of trace t.
+func NewZeta(p, t *big.Int) *Zeta {
+	return &Zeta{P: new(big.Int).Set(p), T: new(big.Int).Set(t)}
+}
+
+// ZetaFromCount Instantiate the zeta function of the curve from its number of points N over F_p (e.g. Schoof(curve).N):
+// t = p + 1 - N.
+func ZetaFromCount(curve *ec.EllipticCurve, N *big.Int) *Zeta {
+	t := new(big.Int).Add(curve.GetP(), big.NewInt(1))
+	t.Sub(t, N)
+	return NewZeta(curve.GetP(), t)
+}
+
+// LPolynomial Returns the integer coefficients of L(T), by increasing degree: [1, -t, p].
+func (z *Zeta) LPolynomial() []*big.Int {
+	return []*big.Int{big.NewInt(1), new(big.Int).Neg(z.T), new(big.Int).Set(z.P)}
+}
+
+// Trace Returns t_k = α^k + β^k (k >= 0), the trace of the Frobenius of F_(p^k), by the recurrence
+// t_0 = 2, t_1 = t, t_k = t·t_(k-1) - p·t_(k-2), α and β being the roots of X² - tX + p.
+func (z *Zeta) Trace(k int) *big.Int {
+	if k < 0 {
+		panic("Zeta.Trace: k must be >= 0")
+	}
+	prev := big.NewInt(2)        // t_0
+	cur := new(big.Int).Set(z.T) // t_1
+	if k == 0 {
+		return prev
+	}
+	for i := 1; i < k; i++ {
+		next := new(big.Int).Mul(z.T, cur)
+		next.Sub(next, new(big.Int).Mul(z.P, prev))
+		prev, cur = cur, next
+	}
+	return cur
+}
+
+// Count Returns #E(F_(p^k)) = p^k + 1 - t_k (k >= 1), see Trace.
+func (z *Zeta) Count(k int) *big.Int {
+	if k < 1 {
+		panic("Zeta.Count: k must be >= 1")
+	}
+	N := new(big.Int).Exp(z.P, big.NewInt(int64(k)), nil)
+	N.Add(N, big.NewInt(1))
+	return N.Sub(N, z.Trace(k))
+}
+
+// Series Returns the first n+1 coefficients z_0..z_n of the power series of Z(T) = exp(sum(#E(F_(p^k)) T^k / k)),
+// integers since 1 / ((1 - T)(1 - pT)) = sum((p^(k+1) - 1) / (p - 1) T^k) = sum((1 + p + ... + p^k) T^k).
+func (z *Zeta) Series(n int) []*big.Int {
+	// c_k = 1 + p + ... + p^k
+	c := make([]*big.Int, n+1)
+	pk := big.NewInt(1)
+	sum := big.NewInt(0)
+	for k := 0; k <= n; k++ {
+		sum = new(big.Int).Add(sum, pk)
+		c[k] = sum
+		pk = new(big.Int).Mul(pk, z.P)
+	}
+
+	L := z.LPolynomial()
+	series := make([]*big.Int, n+1)
+	for k := 0; k <= n; k++ {
+		series[k] = new(big.Int)
+		for j := 0; j < len(L) && j <= k; j++ {
+			series[k].Add(series[k], new(big.Int).Mul(L[j], c[k-j]))
+		}
+	}
+	return series
+}
+
+func (z *Zeta) String() string {
+	sign := "-"
+	if z.T.Sign() < 0 {
+		sign = "+"
+	}
+	return fmt.Sprintf("Z(T) = (1 %s %sT + %sT²) / ((1 - T)(1 - %sT))", sign, new(big.Int).Abs(z.T), z.P, z.P)
+}
+
+// CheckWeil Checks the zeta function against the curve on the first kMax extensions: the counts #E(F_(p^k)) = p^k + 1 - t_k
+// of Count, which follow from the rationality of Z(T) with the L-polynomial 1 - tT + pT² (Weil conjectures), must match
+// the points counted one by one over GF(p^k) (see ec.ExtendedCurve.CountPointsNaive: odd p, p^kMax <= 2^20 only).
+// Returns the first mismatch.
+func (z *Zeta) CheckWeil(curve *ec.EllipticCurve, kMax int) error {
+	if z.P.Cmp(curve.GetP()) != 0 {
+		return fmt.Errorf("Zeta: p = %s, but the curve is over F_%s", z.P, curve.GetP())
+	}
+	for k := 1; k <= kMax; k++ {
+		E, err := curve.OverExtension(k)
+		if err != nil {
+			return err
+		}
+		N, err := E.CountPointsNaive()
+		if err != nil {
+			return err
+		}
+		if want := z.Count(k); N.Cmp(want) != 0 {
+			return fmt.Errorf("Zeta: #E(F_p^%d) = %s from the L-polynomial, but %s points were counted", k, want, N)
+		}
+	}
+	return nil
+}
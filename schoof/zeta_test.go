@@ -0,0 +1,85 @@
+package schoof
+
+import (
+	"goschoof/ec"
+	"math/big"
+	"testing"
+)
+
+func TestZetaLPolynomial(t *testing.T) {
+	L := NewZeta(big.NewInt(101), big.NewInt(-7)).LPolynomial()
+	if len(L) != 3 || L[0].Int64() != 1 || L[1].Int64() != 7 || L[2].Int64() != 101 {
+		t.Errorf("LPolynomial(p = 101, t = -7) = %v, want [1 7 101]", L)
+	}
+}
+
+func TestZetaCount(t *testing.T) {
+	// supersingular y² = x³ + x mod 103, t = 0: t_k = 0 for an odd k and t_2 = -2p, so N_2 = (p + 1)²
+	z := NewZeta(big.NewInt(103), big.NewInt(0))
+	if N := z.Count(1); N.Int64() != 104 {
+		t.Errorf("Count(1) = %s, want 104", N)
+	}
+	if N := z.Count(2); N.Int64() != 104*104 {
+		t.Errorf("Count(2) = %s, want (p + 1)² = %d", N, 104*104)
+	}
+	if N := z.Count(3); N.Int64() != 103*103*103+1 {
+		t.Errorf("Count(3) = %s, want p³ + 1", N)
+	}
+
+	// #E(F_p) divides #E(F_(p^k)), E(F_p) being a subgroup
+	z = NewZeta(big.NewInt(1009), big.NewInt(-37))
+	for k := 1; k <= 8; k++ {
+		if new(big.Int).Mod(z.Count(k), z.Count(1)).Sign() != 0 {
+			t.Errorf("#E(F_p) = %s does not divide #E(F_p^%d) = %s", z.Count(1), k, z.Count(k))
+		}
+	}
+}
+
+func TestZetaSeries(t *testing.T) {
+	// p = 5, t = 2: c_k = 1 + 5 + ... + 5^k = 1, 6, 31, 156 and z_k = c_k - 2c_(k-1) + 5c_(k-2)
+	series := NewZeta(big.NewInt(5), big.NewInt(2)).Series(3)
+	for k, want := range []int64{1, 4, 24, 124} {
+		if series[k].Int64() != want {
+			t.Errorf("Series(3) = %v, want [1 4 24 124]", series)
+			break
+		}
+	}
+
+	// Z(T) = exp(sum(N_k T^k / k)): T·Z'/Z = sum(N_k T^k), i.e. k·z_k = sum(N_j·z_(k-j), j = 1..k)
+	z := NewZeta(big.NewInt(10007), big.NewInt(151))
+	series = z.Series(10)
+	for k := 1; k <= 10; k++ {
+		sum := new(big.Int)
+		for j := 1; j <= k; j++ {
+			sum.Add(sum, new(big.Int).Mul(z.Count(j), series[k-j]))
+		}
+		if sum.Cmp(new(big.Int).Mul(big.NewInt(int64(k)), series[k])) != 0 {
+			t.Errorf("z_%d = %s does not match the point counts", k, series[k])
+		}
+	}
+}
+
+// TestCheckWeil compares the counts of the zeta function with the points counted over GF(p^k), k = 1..3
+func TestCheckWeil(t *testing.T) {
+	cases := []struct{ a, b, p int64 }{{1, 1, 5}, {2, 3, 7}, {1, 0, 11}, {0, 1, 11}, {3, 5, 13}, {4, 2, 23}}
+	for _, c := range cases {
+		curve, err := ec.NewEllipticCurve(big.NewInt(c.a), big.NewInt(c.b), big.NewInt(c.p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		N, err := ec.CountPointsNaive(curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		z := ZetaFromCount(curve, N)
+		if err := z.CheckWeil(curve, 3); err != nil {
+			t.Errorf("y² = x³ + %dx + %d mod %d: %v", c.a, c.b, c.p, err)
+		}
+
+		// a wrong trace is caught
+		wrong := NewZeta(z.P, new(big.Int).Add(z.T, big.NewInt(1)))
+		if err := wrong.CheckWeil(curve, 3); err == nil {
+			t.Errorf("y² = x³ + %dx + %d mod %d: the zeta function of trace t + 1 passed", c.a, c.b, c.p)
+		}
+	}
+}
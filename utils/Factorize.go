@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"math/big"
+	"sort"
+)
+
+// trialDivisionBound primes below are found by trial division, the larger ones by Pollard's rho
+const trialDivisionBound = 1 << 16
+
+// rhoIterations how long Pollard's rho looks for a factor before giving up
+const rhoIterations = 1 << 18
+
+// PrimePower The prime power P^E, a factor of a factorization.
+type PrimePower struct {
+	P *big.Int
+	E int
+}
+
+// Factorize Returns the prime factorization of n (n >= 1), sorted by increasing prime, with trial division
+// then Pollard's rho (Brent's variant). ok is false when a composite factor resisted rho: the factorization is then
+// incomplete, its last factor not being a prime.
+func Factorize(n *big.Int) (factors []PrimePower, ok bool) {
+	rest := new(big.Int).Set(n)
+	counts := make(map[string]*PrimePower)
+	add := func(q *big.Int) {
+		if pp, found := counts[q.String()]; found {
+			pp.E++
+			return
+		}
+		counts[q.String()] = &PrimePower{P: new(big.Int).Set(q), E: 1}
+	}
+
+	q := new(big.Int)
+	r := new(big.Int)
+	for d := int64(2); d < trialDivisionBound && rest.Cmp(big.NewInt(1)) > 0; d++ {
+		q.SetInt64(d)
+		if new(big.Int).Mul(q, q).Cmp(rest) > 0 {
+			break // rest is prime
+		}
+		for {
+			quo, rem := new(big.Int).QuoRem(rest, q, r)
+			if rem.Sign() != 0 {
+				break
+			}
+			rest = quo
+			add(q)
+		}
+	}
+
+	ok = true
+	stack := []*big.Int{rest}
+	for len(stack) > 0 {
+		m := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if m.Cmp(big.NewInt(1)) == 0 {
+			continue
+		}
+		if IsPrimeBigInt(m) {
+			add(m)
+			continue
+		}
+		d := pollardRho(m)
+		if d == nil {
+			ok = false
+			add(m)
+			continue
+		}
+		stack = append(stack, d, new(big.Int).Quo(m, d))
+	}
+
+	for _, pp := range counts {
+		factors = append(factors, *pp)
+	}
+	sort.Slice(factors, func(i, j int) bool { return factors[i].P.Cmp(factors[j].P) < 0 })
+	return factors, ok
+}
+
+// pollardRho Returns a non trivial factor of the composite n, or nil if none was found
+// within rhoIterations (for several polynomials x² + c).
+func pollardRho(n *big.Int) *big.Int {
+	one := big.NewInt(1)
+	for c := int64(1); c <= 3; c++ {
+		cBig := big.NewInt(c)
+		f := func(x *big.Int) *big.Int {
+			x.Mul(x, x)
+			x.Add(x, cBig)
+			return x.Mod(x, n)
+		}
+
+		// Brent: y runs ahead, x is saved at the powers of 2, the gcd batched over 128 steps
+		y := big.NewInt(2)
+		x := new(big.Int)
+		prod := big.NewInt(1)
+		g := big.NewInt(1)
+		diff := new(big.Int)
+		for power, steps := 1, 0; g.Cmp(one) == 0 && steps < rhoIterations; power *= 2 {
+			x.Set(y)
+			for i := 0; i < power && g.Cmp(one) == 0; i++ {
+				f(y)
+				steps++
+				diff.Sub(x, y)
+				prod.Mul(prod, diff.Abs(diff))
+				prod.Mod(prod, n)
+				if i%128 == 127 || i == power-1 {
+					g.GCD(nil, nil, prod, n)
+				}
+			}
+		}
+		if g.Cmp(one) != 0 && g.Cmp(n) != 0 {
+			return g
+		}
+	}
+	return nil
+}